@@ -0,0 +1,166 @@
+package lattice
+
+import "fmt"
+
+// Add returns a new Addr with deltas added element-wise to the leading
+// coordinates; dimensions beyond len(deltas) are unchanged. Deltas may be
+// negative. e.g. Addr{1,2,3}.Add(1, -1) → Addr{2,1,3}.
+// Panics if len(deltas) > Dims() or any result leaves [0, MaxCoordValue].
+func (a Addr) Add(deltas ...int) Addr {
+	addr, err := a.TryAdd(deltas...)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return addr
+}
+
+// Sub returns a new Addr with deltas subtracted element-wise from the
+// leading coordinates. It is Add with every delta negated.
+// Panics if len(deltas) > Dims() or any result leaves [0, MaxCoordValue].
+func (a Addr) Sub(deltas ...int) Addr {
+	addr, err := a.TrySub(deltas...)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return addr
+}
+
+// TryAdd is like Add but returns an error wrapping ErrDimsMismatch,
+// ErrCoordRange or ErrHilbert instead of panicking.
+func (a Addr) TryAdd(deltas ...int) (Addr, error) {
+	return a.offset(deltas, 1)
+}
+
+// TrySub is like Sub but returns an error wrapping ErrDimsMismatch,
+// ErrCoordRange or ErrHilbert instead of panicking.
+func (a Addr) TrySub(deltas ...int) (Addr, error) {
+	return a.offset(deltas, -1)
+}
+
+// offset adds sign*deltas element-wise to the leading coordinates.
+func (a Addr) offset(deltas []int, sign int) (Addr, error) {
+	if err := a.checkMorton(); err != nil {
+		return a, err
+	}
+
+	coords, dims := a.Coords()
+	if len(deltas) > dims {
+		return a, fmt.Errorf("%w: %d deltas for %d dimensions", ErrDimsMismatch, len(deltas), dims)
+	}
+
+	for i, delta := range deltas {
+		v := coords[i] + sign*delta
+		if v < 0 || v > MaxCoordValue {
+			return a, fmt.Errorf("%w: coord[%d]=%d not in [0,%d]", ErrCoordRange, i, v, MaxCoordValue)
+		}
+
+		coords[i] = v
+	}
+
+	return New(coords[:dims]...).withFlags(a), nil
+}
+
+// Incr returns the address one step further along dimension dim
+// e.g. Addr{1,2,3}.Incr(1) → Addr{1,3,3}. ok is false if the coordinate
+// is already MaxCoordValue. Built on With, so no allocation takes place.
+// Panics if dim is out of range [0, Dims()).
+func (a Addr) Incr(dim int) (Addr, bool) {
+	return a.step(dim, 1)
+}
+
+// Decr returns the address one step back along dimension dim
+// e.g. Addr{1,2,3}.Decr(1) → Addr{1,1,3}. ok is false if the coordinate
+// is already 0. Built on With, so no allocation takes place.
+// Panics if dim is out of range [0, Dims()).
+func (a Addr) Decr(dim int) (Addr, bool) {
+	return a.step(dim, -1)
+}
+
+// step adds delta to the coordinate at dim, reporting false if the result
+// leaves [0, MaxCoordValue].
+func (a Addr) step(dim, delta int) (Addr, bool) {
+	a.mustMorton()
+
+	v := a.DecodeDim(dim) + delta
+	if v < 0 || v > MaxCoordValue {
+		return Addr{}, false
+	}
+
+	return a.With(dim, v), true
+}
+
+// Dominates reports whether a Pareto-dominates b: every coordinate of a is
+// greater than or equal to the corresponding coordinate of b and at least
+// one is strictly greater. Incomparable vectors dominate neither way.
+// Panics if a and b have different Dims().
+func (a Addr) Dominates(b Addr) bool {
+	aCoords, bCoords, dims, err := decodePair(a, b)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	strict := false
+
+	for i := range dims {
+		if aCoords[i] < bCoords[i] {
+			return false
+		}
+
+		strict = strict || aCoords[i] > bCoords[i]
+	}
+
+	return strict
+}
+
+// StrictlyDominates reports whether every coordinate of a is strictly
+// greater than the corresponding coordinate of b. It is false for two
+// empty addresses, which have no coordinates to compare.
+// Panics if a and b have different Dims().
+func (a Addr) StrictlyDominates(b Addr) bool {
+	aCoords, bCoords, dims, err := decodePair(a, b)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	for i := range dims {
+		if aCoords[i] <= bCoords[i] {
+			return false
+		}
+	}
+
+	return dims > 0
+}
+
+// Min returns a new Addr holding the element-wise minimum of a and b
+// e.g. Addr{1,5}.Min(Addr{3,2}) → Addr{1,2}.
+// Panics if a and b have different Dims().
+func (a Addr) Min(b Addr) Addr {
+	aCoords, bCoords, dims, err := decodePair(a, b)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	for i := range dims {
+		aCoords[i] = min(aCoords[i], bCoords[i])
+	}
+
+	return New(aCoords[:dims]...).withFlags(a)
+}
+
+// Max returns a new Addr holding the element-wise maximum of a and b
+// e.g. Addr{1,5}.Max(Addr{3,2}) → Addr{3,5}.
+// Panics if a and b have different Dims().
+func (a Addr) Max(b Addr) Addr {
+	aCoords, bCoords, dims, err := decodePair(a, b)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	for i := range dims {
+		aCoords[i] = max(aCoords[i], bCoords[i])
+	}
+
+	return New(aCoords[:dims]...).withFlags(a)
+}
@@ -0,0 +1,396 @@
+package lattice_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aclivo/lattice"
+)
+
+// ============================================================
+// Add / Sub
+// ============================================================
+
+func TestAdd(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		addr   lattice.Addr
+		deltas []int
+		want   lattice.Addr
+	}{
+		{"no deltas", lattice.New(1, 2, 3), nil, lattice.New(1, 2, 3)},
+		{"all dims", lattice.New(1, 2, 3), []int{10, 20, 30}, lattice.New(11, 22, 33)},
+		{"leading dims only", lattice.New(1, 2, 3), []int{1, -1}, lattice.New(2, 1, 3)},
+		{"negative to zero", lattice.New(5, 5), []int{-5, 0}, lattice.New(0, 5)},
+		{"up to max", lattice.New(lattice.MaxCoordValue - 1), []int{1}, lattice.New(lattice.MaxCoordValue)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := testCase.addr.Add(testCase.deltas...); got != testCase.want {
+				t.Errorf("Add(%v) = %v, want %v", testCase.deltas, got, testCase.want)
+			}
+
+			if got := testCase.want.Sub(testCase.deltas...); got != testCase.addr {
+				t.Errorf("Sub(%v) = %v, want %v", testCase.deltas, got, testCase.addr)
+			}
+		})
+	}
+}
+
+func TestTryAdd_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		addr    lattice.Addr
+		deltas  []int
+		sub     bool
+		wantErr error
+	}{
+		{"too many deltas", lattice.New(1, 2), []int{1, 2, 3}, false, lattice.ErrDimsMismatch},
+		{"underflow", lattice.New(1, 2), []int{0, -3}, false, lattice.ErrCoordRange},
+		{"overflow", lattice.New(lattice.MaxCoordValue), []int{1}, false, lattice.ErrCoordRange},
+		{"sub underflow", lattice.New(1, 2), []int{2}, true, lattice.ErrCoordRange},
+		{"sub overflow", lattice.New(lattice.MaxCoordValue), []int{-1}, true, lattice.ErrCoordRange},
+		{"sub too many deltas", lattice.New(), []int{1}, true, lattice.ErrDimsMismatch},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			try := testCase.addr.TryAdd
+			if testCase.sub {
+				try = testCase.addr.TrySub
+			}
+
+			got, err := try(testCase.deltas...)
+			if !errors.Is(err, testCase.wantErr) {
+				t.Errorf("error = %v, want %v", err, testCase.wantErr)
+			}
+
+			if got != testCase.addr {
+				t.Errorf("result on error = %v, want receiver %v", got, testCase.addr)
+			}
+		})
+	}
+}
+
+func TestTry_Errors(t *testing.T) {
+	t.Parallel()
+
+	a := lattice.New(1, lattice.MaxCoordValue/2+1, 3)
+
+	tests := []struct {
+		name    string
+		try     func() (lattice.Addr, error)
+		wantErr error
+		wantMsg string
+	}{
+		{"TryAdd", func() (lattice.Addr, error) { return a.TryAdd(0, lattice.MaxCoordValue) }, lattice.ErrCoordRange, "coord[1]="},
+		{"TrySub", func() (lattice.Addr, error) { return a.TrySub(0, 0, 4) }, lattice.ErrCoordRange, "coord[2]=-1"},
+		{"TryWith value", func() (lattice.Addr, error) { return a.TryWith(2, lattice.MaxCoordValue+1) }, lattice.ErrCoordRange, "coord[2]=1048576"},
+		{"TryWith negative", func() (lattice.Addr, error) { return a.TryWith(0, -1) }, lattice.ErrCoordRange, "coord[0]=-1"},
+		{"TryWith index", func() (lattice.Addr, error) { return a.TryWith(3, 0) }, lattice.ErrDimIndex, "3 not in [0:3]"},
+		{"TryAppend value", func() (lattice.Addr, error) { return a.TryAppend(4, -5) }, lattice.ErrCoordRange, "coord[4]=-5"},
+		{"TryAppend dims", func() (lattice.Addr, error) { return a.TryAppend(make([]int, 10)...) }, lattice.ErrTooManyDims, "13 coordinates"},
+		{"TrySlice bounds", func() (lattice.Addr, error) { return a.TrySlice(1, 4) }, lattice.ErrDimIndex, "slice [1:4]"},
+		{"TrySlice reversed", func() (lattice.Addr, error) { return a.TrySlice(2, 1) }, lattice.ErrDimIndex, "slice [2:1]"},
+		{"TryScale overflow", func() (lattice.Addr, error) { return a.TryScale(2) }, lattice.ErrCoordRange, "coord[1]=524288 scaled by 2"},
+		{"TryScale negative", func() (lattice.Addr, error) { return a.TryScale(-1) }, lattice.ErrCoordRange, "scale factor -1"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := testCase.try()
+			if !errors.Is(err, testCase.wantErr) {
+				t.Fatalf("error = %v, want %v", err, testCase.wantErr)
+			}
+
+			if !strings.Contains(err.Error(), testCase.wantMsg) {
+				t.Errorf("error %q does not mention %q", err, testCase.wantMsg)
+			}
+
+			if got != a {
+				t.Errorf("result on error = %v, want receiver %v", got, a)
+			}
+		})
+	}
+}
+
+func TestTry_MatchesPanicking(t *testing.T) {
+	t.Parallel()
+
+	a := lattice.New(1, 2, 3)
+
+	check := func(name string, got lattice.Addr, err error, want lattice.Addr) {
+		t.Helper()
+
+		if err != nil || got != want {
+			t.Errorf("%s = %v, %v; want %v, nil", name, got, err, want)
+		}
+	}
+
+	got, err := a.TryWith(1, 9)
+	check("TryWith", got, err, a.With(1, 9))
+
+	got, err = a.TryAppend(4, 5)
+	check("TryAppend", got, err, a.Append(4, 5))
+
+	got, err = a.TrySlice(1, 3)
+	check("TrySlice", got, err, a.Slice(1, 3))
+
+	got, err = a.TryScale(3)
+	check("TryScale", got, err, a.Scale(3))
+
+	got, err = a.TryScale(0)
+	check("TryScale(0)", got, err, a.Scale(0))
+}
+
+func TestAdd_PanicMessage(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		rec := recover()
+
+		if rec == nil {
+			t.Error("expected panic")
+
+			return
+		}
+
+		want := fmt.Sprintf("lattice: coordinate out of range: coord[1]=-1 not in [0,%d]", lattice.MaxCoordValue)
+		if got := fmt.Sprintf("%v", rec); got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	lattice.New(1, 0).Add(0, -1)
+}
+
+// ============================================================
+// Incr / Decr
+// ============================================================
+
+func TestIncrDecr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		a      lattice.Addr
+		dim    int
+		incr   lattice.Addr
+		incrOK bool
+		decr   lattice.Addr
+		decrOK bool
+	}{
+		{"interior", lattice.New(1, 2, 3), 1, lattice.New(1, 3, 3), true, lattice.New(1, 1, 3), true},
+		{"zero", lattice.New(0, 5), 0, lattice.New(1, 5), true, lattice.Addr{}, false},
+		{"max", lattice.New(5, lattice.MaxCoordValue), 1, lattice.Addr{}, false, lattice.New(5, lattice.MaxCoordValue-1), true},
+		{"last of 12", lattice.New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12), 11,
+			lattice.New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 13), true,
+			lattice.New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 11), true},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got, ok := testCase.a.Incr(testCase.dim); got != testCase.incr || ok != testCase.incrOK {
+				t.Errorf("Incr(%d) = %v, %v; want %v, %v", testCase.dim, got, ok, testCase.incr, testCase.incrOK)
+			}
+
+			if got, ok := testCase.a.Decr(testCase.dim); got != testCase.decr || ok != testCase.decrOK {
+				t.Errorf("Decr(%d) = %v, %v; want %v, %v", testCase.dim, got, ok, testCase.decr, testCase.decrOK)
+			}
+		})
+	}
+}
+
+func TestIncrDecr_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	a := lattice.New(7, 0, lattice.MaxCoordValue)
+
+	for dim := range a.Dims() {
+		if up, ok := a.Incr(dim); ok {
+			if back, ok := up.Decr(dim); !ok || back != a {
+				t.Errorf("Incr(%d).Decr(%d) = %v, %v; want %v", dim, dim, back, ok, a)
+			}
+		}
+	}
+}
+
+func TestIncr_PanicDim(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if got, want := fmt.Sprintf("%v", recover()), "lattice: dimension index 2 out of range [0:2]"; got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	lattice.New(1, 2).Incr(2)
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestIncrDecr_ZeroAllocs(t *testing.T) {
+	a := lattice.New(10, 20, 30)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		a, _ = a.Incr(1)
+		a, _ = a.Decr(1)
+	})
+	if allocs != 0 {
+		t.Errorf("Incr/Decr allocs = %v, want 0", allocs)
+	}
+}
+
+// ============================================================
+// Dominates / StrictlyDominates
+// ============================================================
+
+func TestDominates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		a, b       lattice.Addr
+		dominates  bool
+		strictly   bool
+		bDominates bool
+	}{
+		{"empty", lattice.New(), lattice.New(), false, false, false},
+		{"equal", lattice.New(3, 3), lattice.New(3, 3), false, false, false},
+		{"greater on one axis", lattice.New(3, 4), lattice.New(3, 3), true, false, false},
+		{"greater on all axes", lattice.New(4, 4), lattice.New(3, 3), true, true, false},
+		{"less on all axes", lattice.New(2, 2), lattice.New(3, 3), false, false, true},
+		{"incomparable", lattice.New(5, 1), lattice.New(1, 5), false, false, false},
+		{"incomparable 3D", lattice.New(5, 5, 1), lattice.New(1, 1, 2), false, false, false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := testCase.a.Dominates(testCase.b); got != testCase.dominates {
+				t.Errorf("a.Dominates(b) = %v, want %v", got, testCase.dominates)
+			}
+
+			if got := testCase.a.StrictlyDominates(testCase.b); got != testCase.strictly {
+				t.Errorf("a.StrictlyDominates(b) = %v, want %v", got, testCase.strictly)
+			}
+
+			if got := testCase.b.Dominates(testCase.a); got != testCase.bDominates {
+				t.Errorf("b.Dominates(a) = %v, want %v", got, testCase.bDominates)
+			}
+		})
+	}
+}
+
+func TestDominates_ParetoFront(t *testing.T) {
+	t.Parallel()
+
+	points := []lattice.Addr{lattice.New(1, 5), lattice.New(2, 2), lattice.New(5, 1), lattice.New(3, 3), lattice.New(1, 1), lattice.New(4, 2)}
+	want := map[lattice.Addr]bool{lattice.New(1, 5): true, lattice.New(5, 1): true, lattice.New(3, 3): true, lattice.New(4, 2): true}
+
+	for _, p := range points {
+		dominated := false
+
+		for _, q := range points {
+			if q.Dominates(p) {
+				dominated = true
+
+				break
+			}
+		}
+
+		if !dominated != want[p] {
+			t.Errorf("%v on Pareto front = %v, want %v", p, !dominated, want[p])
+		}
+	}
+}
+
+func TestDominates_PanicDimsMismatch(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic with mismatched dimensions")
+		}
+	}()
+
+	lattice.New(1, 2).Dominates(lattice.New(1))
+}
+
+// ============================================================
+// Min / Max
+// ============================================================
+
+func TestMinMax(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		a, b    lattice.Addr
+		wantMin lattice.Addr
+		wantMax lattice.Addr
+	}{
+		{"empty", lattice.New(), lattice.New(), lattice.New(), lattice.New()},
+		{"equal", lattice.New(3, 3), lattice.New(3, 3), lattice.New(3, 3), lattice.New(3, 3)},
+		{"a dominates", lattice.New(5, 6, 7), lattice.New(1, 2, 3), lattice.New(1, 2, 3), lattice.New(5, 6, 7)},
+		{"b dominates", lattice.New(1, 2, 3), lattice.New(5, 6, 7), lattice.New(1, 2, 3), lattice.New(5, 6, 7)},
+		{"interleaved", lattice.New(1, 9, 4), lattice.New(8, 2, 4), lattice.New(1, 2, 4), lattice.New(8, 9, 4)},
+		{"extremes", lattice.New(0, lattice.MaxCoordValue), lattice.New(lattice.MaxCoordValue, 0), lattice.New(0, 0), lattice.New(lattice.MaxCoordValue, lattice.MaxCoordValue)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := testCase.a.Min(testCase.b); got != testCase.wantMin {
+				t.Errorf("Min() = %v, want %v", got, testCase.wantMin)
+			}
+
+			if got := testCase.a.Max(testCase.b); got != testCase.wantMax {
+				t.Errorf("Max() = %v, want %v", got, testCase.wantMax)
+			}
+
+			if got := testCase.b.Min(testCase.a); got != testCase.wantMin {
+				t.Errorf("Min() not commutative: %v, want %v", got, testCase.wantMin)
+			}
+		})
+	}
+}
+
+func TestMinMax_PanicDimsMismatch(t *testing.T) {
+	t.Parallel()
+
+	ops := map[string]func(a, b lattice.Addr) lattice.Addr{
+		"Min": lattice.Addr.Min,
+		"Max": lattice.Addr.Max,
+	}
+
+	for name, op := range ops {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic with mismatched dimensions")
+				}
+			}()
+
+			op(lattice.New(1, 2), lattice.New(1))
+		})
+	}
+}
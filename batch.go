@@ -0,0 +1,55 @@
+package lattice
+
+import (
+	"fmt"
+	"slices"
+)
+
+// NewBatch encodes each row of coordinates as New would and appends the
+// addresses to dst, returning the extended slice; pass dst[:0] to reuse its
+// storage. Nothing is allocated beyond growing dst.
+//
+// If a row is invalid, NewBatch stops and returns dst extended with the
+// rows before it, and an error naming the row index and wrapping
+// ErrTooManyDims or ErrCoordRange.
+func NewBatch(dst []Addr, rows [][]int) ([]Addr, error) {
+	dst = slices.Grow(dst, len(rows))
+
+	for i, row := range rows {
+		if err := ValidCoords(row...); err != nil {
+			return dst, fmt.Errorf("row %d: %w", i, err)
+		}
+
+		dst = append(dst, encode(row))
+	}
+
+	return dst, nil
+}
+
+// DecodeBatch decodes every address into dst back to back, for export to
+// columnar stores. Address i occupies coords[offsets[i]:offsets[i+1]]:
+// offsets has len(addrs)+1 entries, starting at 0 and ending at
+// len(coords), the total number of coordinates. coords is dst[:len(coords)].
+// Panics if dst is shorter than the total number of coordinates.
+func DecodeBatch(addrs []Addr, dst []int) (coords []int, offsets []int) {
+	total := 0
+	for _, a := range addrs {
+		total += a.Dims()
+	}
+
+	if len(dst) < total {
+		panic(fmt.Sprintf("lattice: dst too small: need %d, got %d", total, len(dst)))
+	}
+
+	offsets = make([]int, len(addrs)+1)
+	pos := 0
+
+	for i, a := range addrs {
+		offsets[i] = pos
+		pos += len(a.CoordsSlice(dst[pos:]))
+	}
+
+	offsets[len(addrs)] = pos
+
+	return dst[:pos], offsets
+}
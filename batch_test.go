@@ -0,0 +1,163 @@
+package lattice_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/aclivo/lattice"
+)
+
+// ============================================================
+// NewBatch
+// ============================================================
+
+func TestNewBatch(t *testing.T) {
+	t.Parallel()
+
+	rows := [][]int{{}, {1}, {1, 2}, {1, 2, 3}, {lattice.MaxCoordValue, 0, 7, 9}}
+
+	got, err := lattice.NewBatch(nil, rows)
+	if err != nil {
+		t.Fatalf("NewBatch() error = %v", err)
+	}
+
+	want := make([]lattice.Addr, 0, len(rows))
+	for _, row := range rows {
+		want = append(want, lattice.New(row...))
+	}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("NewBatch() = %v, want %v", got, want)
+	}
+}
+
+func TestNewBatch_AppendsToDst(t *testing.T) {
+	t.Parallel()
+
+	dst := []lattice.Addr{lattice.New(9)}
+
+	got, err := lattice.NewBatch(dst, [][]int{{1}, {2}})
+	if err != nil {
+		t.Fatalf("NewBatch() error = %v", err)
+	}
+
+	if !slices.Equal(got, []lattice.Addr{lattice.New(9), lattice.New(1), lattice.New(2)}) {
+		t.Errorf("NewBatch() = %v, want [Addr[9] Addr[1] Addr[2]]", got)
+	}
+}
+
+func TestNewBatch_BadRow(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rows    [][]int
+		wantErr error
+		wantLen int
+		wantMsg string
+	}{
+		{"coord out of range", [][]int{{1, 2}, {3, 4}, {5, -6}, {7, 8}}, lattice.ErrCoordRange, 2, "row 2:"},
+		{"too many dims", [][]int{make([]int, 13)}, lattice.ErrTooManyDims, 0, "row 0:"},
+		{"too large", [][]int{{1}, {lattice.MaxCoordValue + 1}}, lattice.ErrCoordRange, 1, "row 1:"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := lattice.NewBatch(nil, testCase.rows)
+			if !errors.Is(err, testCase.wantErr) {
+				t.Fatalf("NewBatch() error = %v, want %v", err, testCase.wantErr)
+			}
+
+			if !strings.HasPrefix(err.Error(), testCase.wantMsg) {
+				t.Errorf("NewBatch() error = %q, want prefix %q", err, testCase.wantMsg)
+			}
+
+			if len(got) != testCase.wantLen {
+				t.Errorf("NewBatch() encoded %d rows before the error, want %d", len(got), testCase.wantLen)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestNewBatch_ZeroAllocsWithCapacity(t *testing.T) {
+	rows := make([][]int, 100)
+	for i := range rows {
+		rows[i] = []int{i, i + 1, i + 2, i + 3}
+	}
+
+	dst := make([]lattice.Addr, 0, len(rows))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		dst, _ = lattice.NewBatch(dst[:0], rows)
+	})
+	if allocs != 0 {
+		t.Errorf("NewBatch allocs = %v, want 0", allocs)
+	}
+}
+
+func BenchmarkNewBatch_3D(b *testing.B) {
+	rows := make([][]int, 1000)
+	for i := range rows {
+		rows[i] = []int{i, i * 2, i * 3}
+	}
+
+	dst := make([]lattice.Addr, 0, len(rows))
+
+	for b.Loop() {
+		dst, _ = lattice.NewBatch(dst[:0], rows)
+	}
+}
+
+// ============================================================
+// DecodeBatch
+// ============================================================
+
+func TestDecodeBatch(t *testing.T) {
+	t.Parallel()
+
+	addrs := []lattice.Addr{lattice.New(1, 2, 3), lattice.New(), lattice.New(lattice.MaxCoordValue), lattice.New(4, 5), lattice.New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)}
+	dst := make([]int, 20)
+
+	coords, offsets := lattice.DecodeBatch(addrs, dst)
+
+	if len(coords) != 18 || &coords[0] != &dst[0] {
+		t.Errorf("len(coords) = %d, want 18 backed by dst", len(coords))
+	}
+
+	if want := []int{0, 3, 3, 4, 6, 18}; !slices.Equal(offsets, want) {
+		t.Errorf("offsets = %v, want %v", offsets, want)
+	}
+
+	for i, addr := range addrs {
+		if got := lattice.New(coords[offsets[i]:offsets[i+1]]...); got != addr {
+			t.Errorf("address %d rebuilt as %v, want %v", i, got, addr)
+		}
+	}
+}
+
+func TestDecodeBatch_Empty(t *testing.T) {
+	t.Parallel()
+
+	coords, offsets := lattice.DecodeBatch(nil, nil)
+	if len(coords) != 0 || !slices.Equal(offsets, []int{0}) {
+		t.Errorf("DecodeBatch(nil) = %v, %v, want [], [0]", coords, offsets)
+	}
+}
+
+func TestDecodeBatch_PanicSmallDst(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if got, want := fmt.Sprintf("%v", recover()), "lattice: dst too small: need 5, got 4"; got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	lattice.DecodeBatch([]lattice.Addr{lattice.New(1, 2), lattice.New(3, 4, 5)}, make([]int, 4))
+}
@@ -0,0 +1,114 @@
+package lattice
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// addrBits is the total number of bits in an Addr.
+const addrBits = 4 * bitsPerWord
+
+// ErrLayout is returned by NewCodec when the requested layout does not fit in an Addr.
+var ErrLayout = errors.New("lattice: invalid codec layout")
+
+// Codec encodes coordinates into an Addr using a caller-chosen split of the
+// 254 bits below the encoding flags between coordinate width and dimension
+// count. The header holds the dimension count in just enough bits for
+// maxDims, followed by the Z-order interleaved coordinates.
+//
+// NewCodec(BitsPerCoord, MaxDimensions) reproduces the layout of New exactly.
+// Other layouts are not interchangeable with it: decode a Codec's addresses
+// only with a Codec of the same layout, and do not call Addr methods that
+// decode coordinates (Coords, At, String, ...) on them. They remain
+// comparable and usable as map keys.
+type Codec struct {
+	bitsPerCoord int
+	maxDims      int
+	headerBits   int
+}
+
+// NewCodec returns a Codec storing up to maxDims coordinates of
+// bitsPerCoord bits each e.g. NewCodec(40, 6) or NewCodec(10, 24).
+// Returns an error wrapping ErrLayout unless both are positive,
+// bitsPerCoord leaves coordinates representable as int, and the header
+// plus maxDims*bitsPerCoord fits below the two flag bits at the top of the
+// address, in 254 bits.
+func NewCodec(bitsPerCoord, maxDims int) (*Codec, error) {
+	if bitsPerCoord < 1 || bitsPerCoord >= bits.UintSize {
+		return nil, fmt.Errorf("%w: %d bits per coordinate not in [1,%d]", ErrLayout, bitsPerCoord, bits.UintSize-1)
+	}
+
+	if maxDims < 1 {
+		return nil, fmt.Errorf("%w: max dimensions %d must be positive", ErrLayout, maxDims)
+	}
+
+	headerBits := bits.Len(uint(maxDims))
+
+	if maxDims > (addrBits-flagBits-headerBits)/bitsPerCoord {
+		return nil, fmt.Errorf("%w: %d dimensions × %d bits + %d header bits exceeds %d",
+			ErrLayout, maxDims, bitsPerCoord, headerBits, addrBits-flagBits)
+	}
+
+	return &Codec{bitsPerCoord: bitsPerCoord, maxDims: maxDims, headerBits: headerBits}, nil
+}
+
+// MaxCoord returns the largest coordinate value the codec can store.
+func (c *Codec) MaxCoord() int {
+	return 1<<c.bitsPerCoord - 1
+}
+
+// MaxDims returns the largest number of dimensions the codec can store.
+func (c *Codec) MaxDims() int {
+	return c.maxDims
+}
+
+// Encode builds an address from coords using the codec's layout.
+// Returns an error wrapping ErrTooManyDims or ErrCoordRange on invalid input.
+func (c *Codec) Encode(coords ...int) (Addr, error) {
+	dims := len(coords)
+	if dims > c.maxDims {
+		return Addr{}, fmt.Errorf("%w: %d coordinates, max %d", ErrTooManyDims, dims, c.maxDims)
+	}
+
+	maxCoord := c.MaxCoord()
+
+	for i, v := range coords {
+		if v < 0 || v > maxCoord {
+			return Addr{}, fmt.Errorf("%w: coord[%d]=%d not in [0,%d]", ErrCoordRange, i, v, maxCoord)
+		}
+	}
+
+	addr := Addr{uint64(dims)}
+
+	for bitPos := range c.bitsPerCoord {
+		for dimIdx, v := range coords {
+			if v>>bitPos&1 != 0 {
+				addr.orBits(c.headerBits+bitPos*dims+dimIdx, 1)
+			}
+		}
+	}
+
+	return addr, nil
+}
+
+// Decode returns the coordinates of an address built by Encode on a codec
+// with the same layout, and their count.
+// Panics if the header holds more than MaxDims dimensions, which means a
+// was not built by a codec of this layout.
+func (c *Codec) Decode(a Addr) ([]int, int) {
+	dims := int(a[0] & (1<<c.headerBits - 1)) //nolint:gosec // masked to headerBits bits
+	if dims > c.maxDims {
+		panic(fmt.Sprintf("lattice: codec header holds %d dimensions, max %d", dims, c.maxDims))
+	}
+
+	coords := make([]int, dims)
+
+	for bitPos := range c.bitsPerCoord {
+		for dimIdx := range dims {
+			coords[dimIdx] |= int(a.bitsAt(c.headerBits+bitPos*dims+dimIdx, 1)) << bitPos //nolint:gosec // single bit
+		}
+	}
+
+	return coords, dims
+}
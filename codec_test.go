@@ -0,0 +1,239 @@
+package lattice
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+	"testing"
+)
+
+// skipWiderThanInt skips t when int cannot hold bitsPerCoord-bit
+// coordinates, as on 32-bit platforms, where NewCodec rejects the layout.
+func skipWiderThanInt(t *testing.T, bitsPerCoord int) {
+	t.Helper()
+
+	if bitsPerCoord >= bits.UintSize {
+		t.Skipf("%d-bit coordinates do not fit in a %d-bit int", bitsPerCoord, bits.UintSize)
+	}
+}
+
+// ============================================================
+// NewCodec
+// ============================================================
+
+func TestNewCodec_Layouts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		bitsPerCoord int
+		maxDims      int
+		wantErr      error
+	}{
+		{"default", BitsPerCoord, MaxDimensions, nil},
+		{"wide coords", 40, 6, nil},
+		{"many dims", 10, 24, nil},
+		{"one bit", 1, 246, nil},
+		{"full word", 63, 3, nil},
+		{"one bit into flags", 1, 247, ErrLayout},
+		{"full word into flags", 63, 4, ErrLayout},
+		{"over budget", 10, 26, ErrLayout},
+		{"wide over budget", 40, 7, ErrLayout},
+		{"zero bits", 0, 4, ErrLayout},
+		{"too wide", 64, 2, ErrLayout},
+		{"widest int", bits.UintSize - 1, 1, nil},
+		{"int width", bits.UintSize, 1, ErrLayout},
+		{"zero dims", 20, 0, ErrLayout},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Coordinates must fit in int, so 32-bit platforms reject
+			// layouts such as NewCodec(40, 6).
+			wantErr := testCase.wantErr
+			if testCase.bitsPerCoord >= bits.UintSize {
+				wantErr = ErrLayout
+			}
+
+			codec, err := NewCodec(testCase.bitsPerCoord, testCase.maxDims)
+			if !errors.Is(err, wantErr) {
+				t.Fatalf("NewCodec(%d, %d) error = %v, want %v", testCase.bitsPerCoord, testCase.maxDims, err, wantErr)
+			}
+
+			if err == nil && codec.MaxCoord() != math.MaxInt>>(bits.UintSize-1-testCase.bitsPerCoord) {
+				t.Errorf("MaxCoord() = %d, want %d bits set", codec.MaxCoord(), testCase.bitsPerCoord)
+			}
+
+			if err == nil && codec.MaxDims() != testCase.maxDims {
+				t.Errorf("MaxDims() = %d, want %d", codec.MaxDims(), testCase.maxDims)
+			}
+		})
+	}
+}
+
+// ============================================================
+// Encode / Decode
+// ============================================================
+
+func TestCodec_MaxLayoutLeavesFlagsClear(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		bitsPerCoord int
+		maxDims      int
+	}{
+		{"one bit", 1, 246},
+		{"62-bit 4-dim", 62, 4},
+		{"default", BitsPerCoord, MaxDimensions},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			skipWiderThanInt(t, testCase.bitsPerCoord)
+
+			codec, err := NewCodec(testCase.bitsPerCoord, testCase.maxDims)
+			if err != nil {
+				t.Fatalf("NewCodec() error = %v", err)
+			}
+
+			coords := make([]int, testCase.maxDims)
+			for i := range coords {
+				coords[i] = codec.MaxCoord()
+			}
+
+			addr, err := codec.Encode(coords...)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+
+			if addr.IsSigned() || addr.IsHilbert() {
+				t.Errorf("Encode(max) = %x: IsSigned() = %v, IsHilbert() = %v, want both false",
+					addr.Words(), addr.IsSigned(), addr.IsHilbert())
+			}
+
+			if got, dims := codec.Decode(addr); dims != testCase.maxDims || !slices.Equal(got, coords) {
+				t.Errorf("Decode(Encode(max)) = %v, %d", got, dims)
+			}
+		})
+	}
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		bitsPerCoord int
+		maxDims      int
+	}{
+		{"40-bit 6-dim", 40, 6},
+		{"10-bit 24-dim", 10, 24},
+		{"default", BitsPerCoord, MaxDimensions},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			skipWiderThanInt(t, testCase.bitsPerCoord)
+
+			codec, err := NewCodec(testCase.bitsPerCoord, testCase.maxDims)
+			if err != nil {
+				t.Fatalf("NewCodec() error = %v", err)
+			}
+
+			maxCoord := codec.MaxCoord()
+
+			for dims := range testCase.maxDims + 1 {
+				coords := make([]int, dims)
+				for i := range coords {
+					coords[i] = (i*7919 + dims) % (maxCoord + 1)
+				}
+
+				if dims > 0 {
+					coords[dims-1] = maxCoord
+				}
+
+				addr, err := codec.Encode(coords...)
+				if err != nil {
+					t.Fatalf("Encode(%v) error = %v", coords, err)
+				}
+
+				got, gotDims := codec.Decode(addr)
+				if gotDims != dims || !slices.Equal(got, coords) {
+					t.Errorf("Decode(Encode(%v)) = %v, %d", coords, got, gotDims)
+				}
+			}
+		})
+	}
+}
+
+func TestCodec_DefaultMatchesNew(t *testing.T) {
+	t.Parallel()
+
+	codec, err := NewCodec(BitsPerCoord, MaxDimensions)
+	if err != nil {
+		t.Fatalf("NewCodec() error = %v", err)
+	}
+
+	for _, coords := range randomCoordSets(200) {
+		addr, err := codec.Encode(coords...)
+		if err != nil {
+			t.Fatalf("Encode(%v) error = %v", coords, err)
+		}
+
+		if want := New(coords...); addr != want {
+			t.Errorf("Encode(%v) = %v, want %v", coords, addr, want)
+		}
+	}
+}
+
+func TestCodec_EncodeRejects(t *testing.T) {
+	t.Parallel()
+	skipWiderThanInt(t, 40)
+
+	codec, err := NewCodec(40, 6)
+	if err != nil {
+		t.Fatalf("NewCodec() error = %v", err)
+	}
+
+	if _, err := codec.Encode(1, 2, 3, 4, 5, 6, 7); !errors.Is(err, ErrTooManyDims) {
+		t.Errorf("Encode(7 coords) error = %v, want %v", err, ErrTooManyDims)
+	}
+
+	maxCoord := codec.MaxCoord()
+
+	if _, err := codec.Encode(1, maxCoord+1); !errors.Is(err, ErrCoordRange) {
+		t.Errorf("Encode(1<<40) error = %v, want %v", err, ErrCoordRange)
+	}
+
+	if _, err := codec.Encode(-1); !errors.Is(err, ErrCoordRange) {
+		t.Errorf("Encode(-1) error = %v, want %v", err, ErrCoordRange)
+	}
+
+	if addr, err := codec.Encode(maxCoord); err != nil || uint64(maxCoord) != 1<<40-1 {
+		t.Errorf("Encode(max) = %v, %v; MaxCoord() = %d", addr, err, maxCoord)
+	}
+}
+
+func TestCodec_DecodePanicsOnForeignHeader(t *testing.T) {
+	t.Parallel()
+
+	codec, err := NewCodec(10, 24)
+	if err != nil {
+		t.Fatalf("NewCodec() error = %v", err)
+	}
+
+	defer func() {
+		if got, want := fmt.Sprintf("%v", recover()), "lattice: codec header holds 31 dimensions, max 24"; got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	codec.Decode(Addr{31})
+}
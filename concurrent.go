@@ -0,0 +1,156 @@
+package lattice
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// ShardedMap is a map keyed by Addr that is safe for concurrent use.
+// Keys are spread across independently locked shards by Hash, so writers
+// touching different shards do not contend. Create one with
+// NewShardedMap; the zero value is not usable.
+type ShardedMap[V any] struct {
+	shards []mapShard[V]
+}
+
+// mapShard is one lock-protected partition of a ShardedMap.
+type mapShard[V any] struct {
+	mu sync.RWMutex
+	m  map[Addr]V
+}
+
+// NewShardedMap returns an empty ShardedMap with the given number of
+// shards. A few times GOMAXPROCS is a good starting point for write-heavy
+// workloads. Panics if shards < 1.
+func NewShardedMap[V any](shards int) *ShardedMap[V] {
+	if shards < 1 {
+		panic(fmt.Sprintf("lattice: shard count %d must be positive", shards))
+	}
+
+	m := &ShardedMap[V]{shards: make([]mapShard[V], shards)}
+	for i := range m.shards {
+		m.shards[i].m = make(map[Addr]V)
+	}
+
+	return m
+}
+
+// shard returns the shard owning a.
+func (m *ShardedMap[V]) shard(a Addr) *mapShard[V] {
+	return &m.shards[a.Hash()%uint64(len(m.shards))]
+}
+
+// Get returns the value stored at a and whether it was present.
+func (m *ShardedMap[V]) Get(a Addr) (V, bool) {
+	s := m.shard(a)
+
+	s.mu.RLock()
+	v, ok := s.m[a]
+	s.mu.RUnlock()
+
+	return v, ok
+}
+
+// Set stores v at a, replacing any previous value.
+func (m *ShardedMap[V]) Set(a Addr, v V) {
+	s := m.shard(a)
+
+	s.mu.Lock()
+	s.m[a] = v
+	s.mu.Unlock()
+}
+
+// Delete removes the value stored at a, if any.
+func (m *ShardedMap[V]) Delete(a Addr) {
+	s := m.shard(a)
+
+	s.mu.Lock()
+	delete(s.m, a)
+	s.mu.Unlock()
+}
+
+// Len returns the number of stored addresses. Shards are counted one at a
+// time, so under concurrent writes the result is a snapshot that may
+// never have been the exact size at any single instant.
+func (m *ShardedMap[V]) Len() int {
+	n := 0
+
+	for i := range m.shards {
+		s := &m.shards[i]
+
+		s.mu.RLock()
+		n += len(s.m)
+		s.mu.RUnlock()
+	}
+
+	return n
+}
+
+// CounterMap holds int64 counters in sparse cells and is safe for
+// concurrent use without caller-side locking. Once a cell exists,
+// increments are single atomic adds, so hot cells do not serialize on a
+// lock. The zero value is an empty map ready to use.
+// A CounterMap must not be copied after first use.
+type CounterMap struct {
+	m sync.Map // Addr → *atomic.Int64
+}
+
+// counter returns the counter for a, creating it if absent.
+func (m *CounterMap) counter(a Addr) *atomic.Int64 {
+	if c, ok := m.m.Load(a); ok {
+		return c.(*atomic.Int64)
+	}
+
+	c, _ := m.m.LoadOrStore(a, new(atomic.Int64))
+
+	return c.(*atomic.Int64)
+}
+
+// Add adds delta to the counter at a. A missing cell starts at delta.
+func (m *CounterMap) Add(a Addr, delta int64) {
+	m.counter(a).Add(delta)
+}
+
+// Inc adds one to the counter at a.
+func (m *CounterMap) Inc(a Addr) {
+	m.Add(a, 1)
+}
+
+// Get returns the counter at a, or zero if nothing was added there.
+func (m *CounterMap) Get(a Addr) int64 {
+	if c, ok := m.m.Load(a); ok {
+		return c.(*atomic.Int64).Load()
+	}
+
+	return 0
+}
+
+// Len returns the number of cells that have been added to.
+//
+// Len and All are eventually consistent: they observe each cell
+// atomically but not the map as a whole, so cells created or updated
+// concurrently may or may not be reflected. Once all writers are done
+// both are exact.
+func (m *CounterMap) Len() int {
+	n := 0
+
+	m.m.Range(func(_, _ any) bool {
+		n++
+
+		return true
+	})
+
+	return n
+}
+
+// All yields every cell and its current counter value in unspecified
+// order. See Len for its consistency under concurrent writes.
+func (m *CounterMap) All() iter.Seq2[Addr, int64] {
+	return func(yield func(Addr, int64) bool) {
+		m.m.Range(func(k, v any) bool {
+			return yield(k.(Addr), v.(*atomic.Int64).Load())
+		})
+	}
+}
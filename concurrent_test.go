@@ -0,0 +1,179 @@
+package lattice_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/aclivo/lattice"
+)
+
+// ============================================================
+// ShardedMap
+// ============================================================
+
+func TestShardedMap_SetGetDelete(t *testing.T) {
+	t.Parallel()
+
+	m := lattice.NewShardedMap[string](4)
+
+	m.Set(lattice.New(1, 2), "a")
+	m.Set(lattice.New(3, 4), "b")
+	m.Set(lattice.New(1, 2), "c")
+
+	if m.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", m.Len())
+	}
+
+	if v, ok := m.Get(lattice.New(1, 2)); !ok || v != "c" {
+		t.Errorf("Get(1,2) = %q, %v; want \"c\", true", v, ok)
+	}
+
+	m.Delete(lattice.New(1, 2))
+	m.Delete(lattice.New(9, 9))
+
+	if _, ok := m.Get(lattice.New(1, 2)); ok {
+		t.Error("Get after Delete: ok = true")
+	}
+
+	if m.Len() != 1 {
+		t.Errorf("Len() after Delete = %d, want 1", m.Len())
+	}
+}
+
+func TestShardedMap_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	const (
+		workers = 16
+		perSide = 32
+	)
+
+	m := lattice.NewShardedMap[int](8)
+
+	var wg sync.WaitGroup
+
+	for w := range workers {
+		wg.Go(func() {
+			// Every worker writes its own column and reads everyone's.
+			for y := range perSide {
+				m.Set(lattice.New(w, y), w*perSide+y)
+
+				for x := range workers {
+					if v, ok := m.Get(lattice.New(x, y)); ok && v != x*perSide+y {
+						t.Errorf("Get(%d,%d) = %d, want %d", x, y, v, x*perSide+y)
+					}
+				}
+
+				_ = m.Len()
+			}
+
+			for y := 0; y < perSide; y += 2 {
+				m.Delete(lattice.New(w, y))
+			}
+		})
+	}
+
+	wg.Wait()
+
+	if got, want := m.Len(), workers*perSide/2; got != want {
+		t.Errorf("Len() = %d, want %d", got, want)
+	}
+
+	for w := range workers {
+		if _, ok := m.Get(lattice.New(w, 1)); !ok {
+			t.Errorf("cell (%d,1) missing", w)
+		}
+	}
+}
+
+func TestNewShardedMap_Panic(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if got, want := fmt.Sprintf("%v", recover()), "lattice: shard count 0 must be positive"; got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	lattice.NewShardedMap[int](0)
+}
+
+// ============================================================
+// CounterMap
+// ============================================================
+
+func TestCounterMap_Basic(t *testing.T) {
+	t.Parallel()
+
+	var m lattice.CounterMap
+
+	m.Inc(lattice.New(1, 2))
+	m.Inc(lattice.New(1, 2))
+	m.Add(lattice.New(3, 4), -5)
+
+	if got := m.Get(lattice.New(1, 2)); got != 2 {
+		t.Errorf("Get(1,2) = %d, want 2", got)
+	}
+
+	if got := m.Get(lattice.New(3, 4)); got != -5 {
+		t.Errorf("Get(3,4) = %d, want -5", got)
+	}
+
+	if got := m.Get(lattice.New(5, 6)); got != 0 {
+		t.Errorf("Get(5,6) = %d, want 0", got)
+	}
+
+	if m.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", m.Len())
+	}
+
+	sum := int64(0)
+	for _, v := range m.All() {
+		sum += v
+	}
+
+	if sum != -3 {
+		t.Errorf("sum over All = %d, want -3", sum)
+	}
+}
+
+func TestCounterMap_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	const (
+		workers = 32
+		rounds  = 1000
+	)
+
+	var (
+		m  lattice.CounterMap
+		wg sync.WaitGroup
+	)
+
+	cells := []lattice.Addr{lattice.New(0, 0), lattice.New(0, 1), lattice.New(1, 0), lattice.New(1, 1)}
+
+	for w := range workers {
+		wg.Go(func() {
+			for i := range rounds {
+				a := cells[(w+i)%len(cells)]
+				m.Inc(a)
+				m.Add(a, 2)
+				_ = m.Get(a)
+			}
+		})
+	}
+
+	wg.Wait()
+
+	want := int64(workers * rounds * 3 / len(cells))
+	for _, a := range cells {
+		if got := m.Get(a); got != want {
+			t.Errorf("Get(%v) = %d, want %d", a, got, want)
+		}
+	}
+
+	if m.Len() != len(cells) {
+		t.Errorf("Len() = %d, want %d", m.Len(), len(cells))
+	}
+}
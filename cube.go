@@ -0,0 +1,212 @@
+package lattice
+
+import (
+	"fmt"
+	"iter"
+)
+
+// RangeScan yields every entry of m whose key satisfies InRange(ranges...).
+// Map iteration order is unspecified, so results are unordered unless the
+// caller sorts them.
+func RangeScan[V any](m map[Addr]V, ranges ...AddrRange) iter.Seq2[Addr, V] {
+	return func(yield func(Addr, V) bool) {
+		for a, v := range m {
+			if a.InRange(ranges...) && !yield(a, v) {
+				return
+			}
+		}
+	}
+}
+
+// SumRange returns the sum of the values of m whose keys satisfy
+// InRange(ranges...), in a single pass that allocates nothing per cell.
+// A -1 bound is a wildcard, exactly as for InRange.
+func SumRange[V Number](m map[Addr]V, ranges ...AddrRange) V {
+	var total V
+
+	for a, v := range m {
+		if a.InRange(ranges...) {
+			total += v
+		}
+	}
+
+	return total
+}
+
+// RollUp collapses dimension dropDim of a sparse cube, returning a new map
+// keyed by each address with that coordinate removed and summing the values
+// that collapse onto the same reduced key
+// e.g. {Addr{1,2,3}: 4, Addr{1,5,3}: 6}.RollUp(1) → {Addr{1,3}: 10}.
+// Panics if the keys do not all share one dimensionality or dropDim is out
+// of range for them.
+func RollUp[V Number](m map[Addr]V, dropDim int) map[Addr]V {
+	out := make(map[Addr]V, len(m))
+	want := -1
+
+	for a, v := range m {
+		a.mustMorton()
+
+		coords, dims := a.Coords()
+
+		if want < 0 {
+			if dropDim < 0 || dropDim >= dims {
+				panic(fmt.Sprintf("lattice: dimension index %d out of range [0:%d]", dropDim, dims))
+			}
+
+			want = dims
+		}
+
+		if dims != want {
+			panic(fmt.Sprintf("%v: key %v has %d dimensions, want %d", ErrDimsMismatch, a, dims, want))
+		}
+
+		copy(coords[dropDim:dims-1], coords[dropDim+1:dims])
+		out[New(coords[:dims-1]...).withFlags(a)] += v
+	}
+
+	return out
+}
+
+// Project returns the marginal distribution of m along dimension dim: a
+// map from each coordinate value on dim to the sum of the values of every
+// key with that coordinate, the one-dimensional extreme of RollUp
+// e.g. {Addr{1,2}: 4, Addr{3,2}: 6, Addr{1,5}: 1}.Project(1) → {2: 10, 5: 1}.
+// Keys may differ in dimensionality as long as each has dimension dim.
+// Panics if dim is out of range for any key.
+func Project[V Number](m map[Addr]V, dim int) map[int]V {
+	out := make(map[int]V)
+
+	for a, v := range m {
+		a.mustMorton()
+
+		out[a.DecodeDim(dim)] += v
+	}
+
+	return out
+}
+
+// Dice returns a new map holding only the entries of m whose keys satisfy
+// InRange(ranges...), the materialized counterpart to RangeScan.
+// Keys are copied unchanged, not re-indexed relative to the ranges.
+func Dice[V any](m map[Addr]V, ranges ...AddrRange) map[Addr]V {
+	out := make(map[Addr]V, diceSizeHint(len(m), ranges))
+
+	for a, v := range m {
+		if a.InRange(ranges...) {
+			out[a] = v
+		}
+	}
+
+	return out
+}
+
+// diceSizeHint estimates how many of n entries survive a Dice: all of them
+// when no dimension is bounded, otherwise a quarter as a modest guess.
+func diceSizeHint(n int, ranges []AddrRange) int {
+	for _, r := range ranges {
+		if r[0] != -1 || r[1] != -1 {
+			return n / 4
+		}
+	}
+
+	return n
+}
+
+// Translate returns a new map with every key of m shifted by deltas via
+// Add, carrying each value along with its key
+// e.g. {Addr{1,2}: 7}.Translate(3, -1) → {Addr{4,1}: 7}.
+// Translation is injective, so no two entries collide.
+// Panics, as Add does, if len(deltas) exceeds a key's dimensionality or a
+// shifted coordinate leaves [0, MaxCoordValue].
+func Translate[V any](m map[Addr]V, deltas ...int) map[Addr]V {
+	out := make(map[Addr]V, len(m))
+
+	for a, v := range m {
+		out[a.Add(deltas...)] = v
+	}
+
+	return out
+}
+
+// Flip returns a new map with every key of m mirrored along dimension dim,
+// its coordinate c replaced by extent-c and its value carried along
+// e.g. {Addr{1,5}: 7}.Flip(0, 9) → {Addr{8,5}: 7}.
+// With extent set to the largest coordinate of a region anchored at the
+// origin, the region maps onto itself. Reflection is injective, so no two
+// entries collide.
+// Panics if dim is out of range for any key, a mirrored coordinate leaves
+// [0, MaxCoordValue], or a key is Hilbert-encoded.
+func Flip[V any](m map[Addr]V, dim, extent int) map[Addr]V {
+	out, err := TryFlip(m, dim, extent)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return out
+}
+
+// TryFlip is like Flip but returns a nil map and the TryWith error for the
+// first offending key (wrapping ErrDimIndex, ErrCoordRange or ErrHilbert)
+// instead of panicking.
+func TryFlip[V any](m map[Addr]V, dim, extent int) (map[Addr]V, error) {
+	out := make(map[Addr]V, len(m))
+
+	for a, v := range m {
+		if err := a.checkMorton(); err != nil {
+			return nil, err
+		}
+
+		if dims := a.Dims(); dim < 0 || dim >= dims {
+			return nil, fmt.Errorf("%w: %d not in [0:%d]", ErrDimIndex, dim, dims)
+		}
+
+		flipped, err := a.TryWith(dim, extent-a.DecodeDim(dim))
+		if err != nil {
+			return nil, err
+		}
+
+		out[flipped] = v
+	}
+
+	return out, nil
+}
+
+// Downsample coarsens m by factor, returning a new map keyed by each key
+// quantized via Quantize(factor). The first value to land on a coarse key
+// is stored as is; each later one is folded in as agg(acc, v)
+// e.g. Downsample({Addr{0,1}: 2, Addr{1,0}: 3, Addr{2,2}: 4}, 2, sum)
+// → {Addr{0,0}: 5, Addr{1,1}: 4}.
+// Map iteration order is unspecified, so agg should be commutative and
+// associative, as sum, min and max are.
+// Panics if factor is not positive, the keys do not all share one
+// dimensionality, or a key is signed or Hilbert-encoded, as for Quantize.
+func Downsample[V Number](m map[Addr]V, factor int, agg func(acc, v V) V) map[Addr]V {
+	if factor <= 0 {
+		panic(fmt.Sprintf("lattice: downsample factor %d must be positive", factor))
+	}
+
+	out := make(map[Addr]V, len(m))
+	want := -1
+
+	for a, v := range m {
+		dims := a.Dims()
+
+		if want < 0 {
+			want = dims
+		}
+
+		if dims != want {
+			panic(fmt.Sprintf("%v: key %v has %d dimensions, want %d", ErrDimsMismatch, a, dims, want))
+		}
+
+		key := a.Quantize(factor)
+
+		if acc, ok := out[key]; ok {
+			v = agg(acc, v)
+		}
+
+		out[key] = v
+	}
+
+	return out
+}
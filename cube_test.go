@@ -0,0 +1,637 @@
+package lattice_test
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/aclivo/lattice"
+)
+
+// ============================================================
+// RangeScan
+// ============================================================
+
+// testCube returns a 3D cube with value x*100 + y*10 + z at every cell
+// of the 10×10×10 box at the origin.
+func testCube() map[lattice.Addr]int {
+	cube := make(map[lattice.Addr]int, 1000)
+
+	for a := range lattice.Box(lattice.New(0, 0, 0), lattice.New(9, 9, 9)) {
+		cube[a] = a.At(0)*100 + a.At(1)*10 + a.At(2)
+	}
+
+	return cube
+}
+
+func TestRangeScan(t *testing.T) {
+	t.Parallel()
+
+	cube := testCube()
+	ranges := []lattice.AddrRange{{2, 4}, {-1, 1}, {7, -1}}
+
+	got := make(map[lattice.Addr]int)
+
+	for a, v := range lattice.RangeScan(cube, ranges...) {
+		if !a.InRange(ranges...) {
+			t.Errorf("RangeScan yielded %v outside %v", a, ranges)
+		}
+
+		if v != cube[a] {
+			t.Errorf("RangeScan yielded %v=%d, want %d", a, v, cube[a])
+		}
+
+		got[a] = v
+	}
+
+	// 3 x-values × 2 y-values × 3 z-values.
+	if len(got) != 18 {
+		t.Errorf("RangeScan yielded %d entries, want 18", len(got))
+	}
+}
+
+func TestRangeScan_NoRangesYieldsAll(t *testing.T) {
+	t.Parallel()
+
+	cube := testCube()
+
+	n := 0
+
+	for range lattice.RangeScan(cube) {
+		n++
+	}
+
+	if n != len(cube) {
+		t.Errorf("RangeScan() yielded %d entries, want %d", n, len(cube))
+	}
+}
+
+func TestRangeScan_EarlyBreak(t *testing.T) {
+	t.Parallel()
+
+	n := 0
+
+	for range lattice.RangeScan(testCube(), lattice.AddrRange{0, 4}) {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+
+	if n != 3 {
+		t.Errorf("iterations = %d, want 3", n)
+	}
+}
+
+// ============================================================
+// SumRange
+// ============================================================
+
+func TestSumRange(t *testing.T) {
+	t.Parallel()
+
+	cube := testCube()
+
+	tests := []struct {
+		name   string
+		ranges []lattice.AddrRange
+		want   int
+	}{
+		// Four cells: x∈{1,2}, y=3, z∈{4,5}.
+		{"subcube", []lattice.AddrRange{{1, 2}, {3, 3}, {4, 5}}, (1+2)*100*2 + 3*10*4 + (4+5)*2},
+		{"single cell", []lattice.AddrRange{{7, 7}, {8, 8}, {9, 9}}, 789},
+		{"empty", []lattice.AddrRange{{5, 4}}, 0},
+		{"wildcard dim", []lattice.AddrRange{{0, 0}, {0, 0}, {-1, -1}}, 45},
+		{"all wildcards", []lattice.AddrRange{{-1, -1}, {-1, -1}, {-1, -1}}, 100 * (4500 + 450 + 45)},
+		{"no ranges", nil, 100 * (4500 + 450 + 45)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := lattice.SumRange(cube, testCase.ranges...); got != testCase.want {
+				t.Errorf("SumRange(%v) = %d, want %d", testCase.ranges, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestSumRange_MatchesRangeScan(t *testing.T) {
+	t.Parallel()
+
+	cube := make(map[lattice.Addr]float64)
+	for a, v := range testCube() {
+		cube[a] = float64(v) / 4
+	}
+
+	ranges := []lattice.AddrRange{{-1, 6}, {2, -1}}
+
+	var want float64
+	for _, v := range lattice.RangeScan(cube, ranges...) {
+		want += v
+	}
+
+	if got := lattice.SumRange(cube, ranges...); got != want {
+		t.Errorf("SumRange() = %v, want %v", got, want)
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestSumRange_ZeroAllocs(t *testing.T) {
+	cube := testCube()
+
+	allocs := testing.AllocsPerRun(10, func() {
+		_ = lattice.SumRange(cube, lattice.AddrRange{1, 5}, lattice.AddrRange{-1, 3})
+	})
+	if allocs != 0 {
+		t.Errorf("SumRange allocs = %v, want 0", allocs)
+	}
+}
+
+// ============================================================
+// RollUp
+// ============================================================
+
+func TestRollUp(t *testing.T) {
+	t.Parallel()
+
+	cube := testCube()
+
+	for dropDim := range 3 {
+		rolled := lattice.RollUp(cube, dropDim)
+
+		if len(rolled) != 100 {
+			t.Errorf("RollUp(%d) has %d cells, want 100", dropDim, len(rolled))
+		}
+
+		if got, want := lattice.SumRange(rolled), lattice.SumRange(cube); got != want {
+			t.Errorf("RollUp(%d) total = %d, want %d", dropDim, got, want)
+		}
+
+		for key, got := range rolled {
+			if key.Dims() != 2 {
+				t.Fatalf("RollUp(%d) key %v has %d dims, want 2", dropDim, key, key.Dims())
+			}
+
+			// Summing the ten cells along the dropped axis.
+			want := 0
+
+			for i := range 10 {
+				coords := []int{key.At(0), key.At(1)}
+				coords = append(coords[:dropDim], append([]int{i}, coords[dropDim:]...)...)
+				want += cube[lattice.New(coords...)]
+			}
+
+			if got != want {
+				t.Errorf("RollUp(%d)[%v] = %d, want %d", dropDim, key, got, want)
+			}
+		}
+	}
+}
+
+func TestRollUp_ToScalar(t *testing.T) {
+	t.Parallel()
+
+	line := map[lattice.Addr]float64{lattice.New(1): 1.5, lattice.New(2): 2.5}
+
+	got := lattice.RollUp(line, 0)
+	if len(got) != 1 || got[lattice.New()] != 4 {
+		t.Errorf("RollUp(line, 0) = %v, want map[Addr[]:4]", got)
+	}
+
+	if got := lattice.RollUp(map[lattice.Addr]int{}, 5); len(got) != 0 {
+		t.Errorf("RollUp(empty) = %v, want empty", got)
+	}
+}
+
+func TestRollUp_Panics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cube    map[lattice.Addr]int
+		dropDim int
+		wantMsg string
+	}{
+		{"dim out of range", map[lattice.Addr]int{lattice.New(1, 2): 1}, 2, "lattice: dimension index 2 out of range [0:2]"},
+		{"negative dim", map[lattice.Addr]int{lattice.New(1, 2): 1}, -1, "lattice: dimension index -1 out of range [0:2]"},
+		{"empty key", map[lattice.Addr]int{lattice.New(): 1}, 0, "lattice: dimension index 0 out of range [0:0]"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != testCase.wantMsg {
+					t.Errorf("panic message = %q, want %q", got, testCase.wantMsg)
+				}
+			}()
+
+			lattice.RollUp(testCase.cube, testCase.dropDim)
+		})
+	}
+}
+
+func TestRollUp_PanicMixedDims(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		rec := recover()
+		if rec == nil {
+			t.Fatal("expected panic")
+		}
+
+		if msg := fmt.Sprintf("%v", rec); !strings.HasPrefix(msg, lattice.ErrDimsMismatch.Error()) {
+			t.Errorf("panic message = %q, want prefix %q", msg, lattice.ErrDimsMismatch.Error())
+		}
+	}()
+
+	lattice.RollUp(map[lattice.Addr]int{lattice.New(1, 2): 1, lattice.New(1, 2, 3): 2}, 0)
+}
+
+// ============================================================
+// Project
+// ============================================================
+
+func TestProject(t *testing.T) {
+	t.Parallel()
+
+	cube := testCube()
+
+	got := lattice.Project(cube, 1)
+	if len(got) != 10 {
+		t.Fatalf("Project has %d values, want 10", len(got))
+	}
+
+	// Each y holds 100 cells: Σx 100x·10 + 100·10y + Σz z·10 = 45000 + 1000y + 450.
+	for y, sum := range got {
+		if want := 45450 + 1000*y; sum != want {
+			t.Errorf("Project[%d] = %d, want %d", y, sum, want)
+		}
+	}
+}
+
+func TestProject_Small(t *testing.T) {
+	t.Parallel()
+
+	m := map[lattice.Addr]float64{
+		lattice.New(1, 2, 0): 4,
+		lattice.New(3, 2, 1): 6,
+		lattice.New(1, 5, 0): 1.5,
+		lattice.New(0, 5):    2,
+	}
+
+	got := lattice.Project(m, 1)
+	want := map[int]float64{2: 10, 5: 3.5}
+
+	if !maps.Equal(got, want) {
+		t.Errorf("Project = %v, want %v", got, want)
+	}
+
+	if total := lattice.Project(m, 0); total[1] != 5.5 || total[3] != 6 || total[0] != 2 {
+		t.Errorf("Project(0) = %v", total)
+	}
+}
+
+func TestProject_PanicDim(t *testing.T) {
+	t.Parallel()
+
+	m := map[lattice.Addr]int{lattice.New(1, 2, 3): 1, lattice.New(4, 5): 2}
+
+	defer func() {
+		if got, want := fmt.Sprintf("%v", recover()), "lattice: dimension index 2 out of range [0:2]"; got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	lattice.Project(m, 2)
+}
+
+// ============================================================
+// Dice
+// ============================================================
+
+func TestDice(t *testing.T) {
+	t.Parallel()
+
+	cube := testCube()
+	ranges := []lattice.AddrRange{{3, 5}, {-1, 2}}
+
+	sub := lattice.Dice(cube, ranges...)
+
+	// 3 x-values × 3 y-values × 10 z-values.
+	if len(sub) != 90 {
+		t.Errorf("len(Dice()) = %d, want 90", len(sub))
+	}
+
+	for a, v := range sub {
+		if want, ok := cube[a]; !ok || v != want {
+			t.Errorf("Dice()[%v] = %d, want %d (present %v)", a, v, want, ok)
+		}
+	}
+
+	for a := range cube {
+		if _, ok := sub[a]; ok != a.InRange(ranges...) {
+			t.Errorf("Dice() contains %v = %v, want %v", a, ok, !ok)
+		}
+	}
+
+	if len(sub) >= len(cube) {
+		t.Errorf("Dice() is not a strict subset: %d of %d entries", len(sub), len(cube))
+	}
+}
+
+func TestDice_Wildcards(t *testing.T) {
+	t.Parallel()
+
+	cube := testCube()
+
+	if got := lattice.Dice(cube, lattice.AddrRange{-1, -1}, lattice.AddrRange{-1, -1}, lattice.AddrRange{-1, -1}); len(got) != len(cube) {
+		t.Errorf("all-wildcard Dice() has %d entries, want %d", len(got), len(cube))
+	}
+
+	if got := lattice.Dice(cube, lattice.AddrRange{-1, -1}, lattice.AddrRange{4, 4}); len(got) != 100 {
+		t.Errorf("Dice(y=4) has %d entries, want 100", len(got))
+	}
+}
+
+func TestDice_DoesNotAliasInput(t *testing.T) {
+	t.Parallel()
+
+	cube := testCube()
+	sub := lattice.Dice(cube, lattice.AddrRange{0, 0})
+
+	sub[lattice.New(0, 0, 0)] = -1
+	delete(sub, lattice.New(0, 0, 1))
+
+	if cube[lattice.New(0, 0, 0)] != 0 || len(cube) != 1000 {
+		t.Error("mutating the Dice result changed the input map")
+	}
+}
+
+// ============================================================
+// Translate
+// ============================================================
+
+func TestTranslate(t *testing.T) {
+	t.Parallel()
+
+	grid := map[lattice.Addr]string{
+		lattice.New(0, 0): "a",
+		lattice.New(1, 0): "b",
+		lattice.New(0, 1): "c",
+		lattice.New(2, 3): "d",
+	}
+
+	got := lattice.Translate(grid, 5, 2)
+
+	want := map[lattice.Addr]string{
+		lattice.New(5, 2): "a",
+		lattice.New(6, 2): "b",
+		lattice.New(5, 3): "c",
+		lattice.New(7, 5): "d",
+	}
+
+	if !maps.Equal(got, want) {
+		t.Errorf("Translate(5, 2) = %v, want %v", got, want)
+	}
+
+	if back := lattice.Translate(got, -5, -2); !maps.Equal(back, grid) {
+		t.Errorf("Translate(-5, -2) did not restore the grid: %v", back)
+	}
+}
+
+func TestTranslate_LeadingDeltas(t *testing.T) {
+	t.Parallel()
+
+	got := lattice.Translate(map[lattice.Addr]int{lattice.New(1, 2, 3): 9, lattice.New(4, 5): 8}, 1)
+
+	if len(got) != 2 || got[lattice.New(2, 2, 3)] != 9 || got[lattice.New(5, 5)] != 8 {
+		t.Errorf("Translate(1) = %v", got)
+	}
+}
+
+func TestTranslate_Panics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		deltas []int
+		want   string
+	}{
+		{"below zero", []int{0, -3}, "lattice: coordinate out of range"},
+		{"too many deltas", []int{1, 1, 1}, "lattice: dimension mismatch"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); !strings.HasPrefix(got, testCase.want) {
+					t.Errorf("panic message = %q, want prefix %q", got, testCase.want)
+				}
+			}()
+
+			lattice.Translate(map[lattice.Addr]int{lattice.New(0, 1): 1, lattice.New(2, 2): 2}, testCase.deltas...)
+		})
+	}
+}
+
+// ============================================================
+// Flip
+// ============================================================
+
+func TestFlip(t *testing.T) {
+	t.Parallel()
+
+	// A 4×3 grid with value 10*x + y at every cell.
+	grid := make(map[lattice.Addr]int, 12)
+
+	for a := range lattice.Box(lattice.New(0, 0), lattice.New(3, 2)) {
+		grid[a] = a.At(0)*10 + a.At(1)
+	}
+
+	got := lattice.Flip(grid, 0, 3)
+
+	if len(got) != len(grid) {
+		t.Fatalf("len(Flip()) = %d, want %d", len(got), len(grid))
+	}
+
+	if v, ok := got[lattice.New(3, 1)]; !ok || v != 1 {
+		t.Errorf("Flip()[(3,1)] = %d (present %v), want 1 from (0,1)", v, ok)
+	}
+
+	for a, v := range got {
+		if want := (3-a.At(0))*10 + a.At(1); v != want {
+			t.Errorf("Flip()[%v] = %d, want %d", a, v, want)
+		}
+	}
+
+	if back := lattice.Flip(got, 0, 3); !maps.Equal(back, grid) {
+		t.Error("flipping twice did not restore the grid")
+	}
+}
+
+func TestFlip_Panics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		dim    int
+		extent int
+		want   string
+	}{
+		{"dimension", 2, 5, "lattice: dimension index out of range: 2 not in [0:2]"},
+		{"negative dimension", -1, 5, "lattice: dimension index out of range: -1 not in [0:2]"},
+		{"below zero", 1, 3, "lattice: coordinate out of range: coord[1]=-1 not in [0,1048575]"},
+		{
+			"above max",
+			0,
+			lattice.MaxCoordValue + 1,
+			fmt.Sprintf("lattice: coordinate out of range: coord[0]=%d not in [0,%d]", lattice.MaxCoordValue+1, lattice.MaxCoordValue),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != testCase.want {
+					t.Errorf("panic message = %q, want %q", got, testCase.want)
+				}
+			}()
+
+			lattice.Flip(map[lattice.Addr]int{lattice.New(0, 4): 1}, testCase.dim, testCase.extent)
+		})
+	}
+}
+
+func TestTryFlip(t *testing.T) {
+	t.Parallel()
+
+	m := map[lattice.Addr]int{lattice.New(0, 4): 1, lattice.New(2, 1): 2}
+
+	got, err := lattice.TryFlip(m, 1, 4)
+	if err != nil || !maps.Equal(got, map[lattice.Addr]int{lattice.New(0, 0): 1, lattice.New(2, 3): 2}) {
+		t.Errorf("TryFlip(m, 1, 4) = %v, %v", got, err)
+	}
+
+	tests := []struct {
+		name        string
+		dim, extent int
+		wantErr     error
+	}{
+		{"dimension", 2, 5, lattice.ErrDimIndex},
+		{"negative dimension", -1, 5, lattice.ErrDimIndex},
+		{"below zero", 1, 3, lattice.ErrCoordRange},
+		{"above max", 0, lattice.MaxCoordValue + 1, lattice.ErrCoordRange},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := lattice.TryFlip(m, testCase.dim, testCase.extent)
+			if !errors.Is(err, testCase.wantErr) || got != nil {
+				t.Errorf("TryFlip(m, %d, %d) = %v, %v, want nil, %v", testCase.dim, testCase.extent, got, err, testCase.wantErr)
+			}
+		})
+	}
+}
+
+// ============================================================
+// Downsample
+// ============================================================
+
+func TestDownsample_Sum(t *testing.T) {
+	t.Parallel()
+
+	cube := testCube()
+	sum := func(acc, v int) int { return acc + v }
+
+	got := lattice.Downsample(cube, 2, sum)
+
+	if len(got) != 125 {
+		t.Fatalf("len(Downsample(2)) = %d, want 125", len(got))
+	}
+
+	// Bucket (0,0,0) holds x,y,z ∈ {0,1}: 4 cells at each coordinate value
+	// contribute 4*(100+10+1) in total.
+	if v := got[lattice.New(0, 0, 0)]; v != 444 {
+		t.Errorf("bucket (0,0,0) = %d, want 444", v)
+	}
+
+	// Bucket (4,2,1) holds x ∈ {8,9}, y ∈ {4,5}, z ∈ {2,3}.
+	if v, want := got[lattice.New(4, 2, 1)], 4*(800+900)+4*(40+50)+4*(2+3); v != want {
+		t.Errorf("bucket (4,2,1) = %d, want %d", v, want)
+	}
+
+	var total int
+
+	for _, v := range got {
+		total += v
+	}
+
+	if want := lattice.SumRange(cube); total != want {
+		t.Errorf("sum over buckets = %d, want %d", total, want)
+	}
+}
+
+func TestDownsample_Max(t *testing.T) {
+	t.Parallel()
+
+	m := map[lattice.Addr]float64{lattice.New(0, 1): 2, lattice.New(1, 0): 3.5, lattice.New(2, 2): 4}
+
+	got := lattice.Downsample(m, 2, func(acc, v float64) float64 { return max(acc, v) })
+
+	if len(got) != 2 || got[lattice.New(0, 0)] != 3.5 || got[lattice.New(1, 1)] != 4 {
+		t.Errorf("Downsample(max) = %v", got)
+	}
+}
+
+func TestDownsample_FactorOne(t *testing.T) {
+	t.Parallel()
+
+	cube := testCube()
+
+	if got := lattice.Downsample(cube, 1, func(acc, v int) int { return acc + v }); !maps.Equal(got, cube) {
+		t.Error("Downsample(1) changed the map")
+	}
+}
+
+func TestDownsample_Panics(t *testing.T) {
+	t.Parallel()
+
+	sum := func(acc, v int) int { return acc + v }
+
+	tests := []struct {
+		name   string
+		m      map[lattice.Addr]int
+		factor int
+		want   string
+	}{
+		{"zero factor", map[lattice.Addr]int{}, 0, "lattice: downsample factor 0 must be positive"},
+		{"negative factor", map[lattice.Addr]int{lattice.New(1): 1}, -2, "lattice: downsample factor -2 must be positive"},
+		{"mixed dims", map[lattice.Addr]int{lattice.New(1, 2): 1, lattice.New(1, 2, 3): 2}, 2, "lattice: dimension mismatch: key"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); !strings.HasPrefix(got, testCase.want) {
+					t.Errorf("panic message = %q, want prefix %q", got, testCase.want)
+				}
+			}()
+
+			lattice.Downsample(testCase.m, testCase.factor, sum)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package lattice
+
+import (
+	"fmt"
+	"math"
+)
+
+// Dense stores one V per address of a bounded box in a flat slice indexed by
+// row-major offset, last dimension fastest (the order Box visits cells).
+// It trades the flexibility of map[Addr]V for contiguous storage when a
+// region is densely populated.
+type Dense[V any] struct {
+	lo     Buffer
+	extent Buffer
+	dims   int
+	flags  uint64
+	values []V
+}
+
+// NewDense returns a Dense covering every address in the inclusive box
+// [lo, hi], with all values zero.
+// The box holds only addresses with lo's encoding, so a signed box never
+// contains an unsigned address with the same stored coordinates.
+// Panics if lo and hi have different dimensions or encodings, if any
+// coordinate of lo exceeds the matching one of hi, or if the box has too
+// many cells to index.
+func NewDense[V any](lo, hi Addr) *Dense[V] {
+	loCoords, hiCoords, dims, err := decodePair(lo, hi)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	flags := lo[flagWord] & flagsMask
+	if hi[flagWord]&flagsMask != flags {
+		panic(fmt.Sprintf("lattice: dense box bounds %v and %v use different encodings", lo, hi))
+	}
+
+	d := &Dense[V]{lo: loCoords, dims: dims, flags: flags}
+	size := 1
+
+	for i := range dims {
+		if loCoords[i] > hiCoords[i] {
+			panic(fmt.Sprintf("lattice: dense box min %v exceeds max %v in dimension %d", lo, hi, i))
+		}
+
+		d.extent[i] = hiCoords[i] - loCoords[i] + 1
+
+		if size > math.MaxInt/d.extent[i] {
+			panic(fmt.Sprintf("lattice: dense box %v..%v has too many cells", lo, hi))
+		}
+
+		size *= d.extent[i]
+	}
+
+	d.values = make([]V, size)
+
+	return d
+}
+
+// Len returns the number of cells in the box.
+func (d *Dense[V]) Len() int {
+	return len(d.values)
+}
+
+// Contains reports whether a lies inside the box.
+func (d *Dense[V]) Contains(a Addr) bool {
+	_, ok := d.offset(a)
+
+	return ok
+}
+
+// Get returns the value stored at a.
+// Panics if a lies outside the box.
+func (d *Dense[V]) Get(a Addr) V {
+	return d.values[d.mustOffset(a)]
+}
+
+// Set stores v at a.
+// Panics if a lies outside the box.
+func (d *Dense[V]) Set(a Addr, v V) {
+	d.values[d.mustOffset(a)] = v
+}
+
+// offset returns the row-major index of a within the box and whether a
+// lies inside it.
+func (d *Dense[V]) offset(a Addr) (int, bool) {
+	coords, dims := a.Coords()
+	if dims != d.dims || a[flagWord]&flagsMask != d.flags {
+		return 0, false
+	}
+
+	off := 0
+
+	for i := range dims {
+		rel := coords[i] - d.lo[i]
+		if rel < 0 || rel >= d.extent[i] {
+			return 0, false
+		}
+
+		off = off*d.extent[i] + rel
+	}
+
+	return off, true
+}
+
+// mustOffset is like offset but panics if a lies outside the box.
+func (d *Dense[V]) mustOffset(a Addr) int {
+	off, ok := d.offset(a)
+	if !ok {
+		panic(fmt.Sprintf("lattice: %v outside dense box", a))
+	}
+
+	return off
+}
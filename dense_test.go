@@ -0,0 +1,183 @@
+package lattice
+
+import (
+	"fmt"
+	"testing"
+)
+
+// ============================================================
+// Dense
+// ============================================================
+
+func TestDense_Corners(t *testing.T) {
+	t.Parallel()
+
+	lo, hi := New(10, 20, 30), New(12, 24, 31)
+	grid := NewDense[int](lo, hi)
+
+	if grid.Len() != 3*5*2 {
+		t.Errorf("Len() = %d, want %d", grid.Len(), 3*5*2)
+	}
+
+	for i, corner := range []Addr{lo, hi, New(10, 24, 31), New(12, 20, 30)} {
+		if !grid.Contains(corner) {
+			t.Errorf("Contains(%v) = false, want true", corner)
+		}
+
+		grid.Set(corner, i+1)
+	}
+
+	for i, corner := range []Addr{lo, hi, New(10, 24, 31), New(12, 20, 30)} {
+		if got := grid.Get(corner); got != i+1 {
+			t.Errorf("Get(%v) = %d, want %d", corner, got, i+1)
+		}
+	}
+
+	if got := grid.Get(New(11, 22, 30)); got != 0 {
+		t.Errorf("Get(unset) = %d, want 0", got)
+	}
+}
+
+func TestDense_OffsetsFollowBox(t *testing.T) {
+	t.Parallel()
+
+	lo, hi := New(1, 2), New(3, 5)
+	grid := NewDense[int](lo, hi)
+
+	want := 0
+
+	for a := range Box(lo, hi) {
+		if off, ok := grid.offset(a); !ok || off != want {
+			t.Errorf("offset(%v) = %d, %v, want %d, true", a, off, ok, want)
+		}
+
+		want++
+	}
+
+	if want != grid.Len() {
+		t.Errorf("Box visited %d cells, Len() = %d", want, grid.Len())
+	}
+}
+
+func TestDense_OutOfBox(t *testing.T) {
+	t.Parallel()
+
+	grid := NewDense[string](New(5, 5), New(6, 6))
+
+	outside := []Addr{New(4, 5), New(7, 6), New(5, 7), New(5), New(5, 5, 5)}
+
+	for _, a := range outside {
+		if grid.Contains(a) {
+			t.Errorf("Contains(%v) = true, want false", a)
+		}
+
+		func() {
+			defer func() {
+				want := fmt.Sprintf("lattice: %v outside dense box", a)
+				if got := fmt.Sprintf("%v", recover()); got != want {
+					t.Errorf("Get(%v) panic = %q, want %q", a, got, want)
+				}
+			}()
+
+			grid.Get(a)
+		}()
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Set(%v) did not panic", a)
+				}
+			}()
+
+			grid.Set(a, "x")
+		}()
+	}
+}
+
+func TestDense_SignedBox(t *testing.T) {
+	t.Parallel()
+
+	grid := NewDense[string](NewSigned(-1, 2), NewSigned(0, 3))
+	grid.Set(NewSigned(-1, 2), "signed")
+
+	if got := grid.Get(NewSigned(-1, 2)); got != "signed" {
+		t.Errorf("Get(NewSigned(-1, 2)) = %q, want %q", got, "signed")
+	}
+
+	// New(524287, 524290) stores the same bits as NewSigned(-1, 2) but
+	// lacks the signed flag, so it lies outside the box.
+	if grid.Contains(New(524287, 524290)) {
+		t.Error("Contains(New(524287, 524290)) = true for a signed box")
+	}
+}
+
+func TestNewDense_Panics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		lo, hi  Addr
+		wantMsg string
+	}{
+		{"mismatch", New(1), New(1, 2), "lattice: dimension mismatch: 1 vs 2 dimensions"},
+		{"inverted", New(1, 5), New(2, 4), "lattice: dense box min Addr[1 5] exceeds max Addr[2 4] in dimension 1"},
+		{
+			"mixed encodings",
+			NewSigned(-1, 2),
+			New(524287, 524290),
+			"lattice: dense box bounds " + NewSigned(-1, 2).String() + " and Addr[524287 524290] use different encodings",
+		},
+		{
+			"too many cells",
+			New(0, 0, 0, 0),
+			New(MaxCoordValue, MaxCoordValue, MaxCoordValue, MaxCoordValue),
+			"lattice: dense box Addr[0 0 0 0]..Addr[1048575 1048575 1048575 1048575] has too many cells",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != testCase.wantMsg {
+					t.Errorf("panic message = %q, want %q", got, testCase.wantMsg)
+				}
+			}()
+
+			NewDense[int](testCase.lo, testCase.hi)
+		})
+	}
+}
+
+func TestDense_ZeroDims(t *testing.T) {
+	t.Parallel()
+
+	grid := NewDense[int](New(), New())
+	grid.Set(New(), 7)
+
+	if grid.Len() != 1 || grid.Get(New()) != 7 {
+		t.Errorf("zero-dim Dense: Len() = %d, Get() = %d, want 1, 7", grid.Len(), grid.Get(New()))
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestDense_ZeroAllocs(t *testing.T) {
+	grid := NewDense[float64](New(0, 0, 0), New(15, 15, 15))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		grid.Set(New(1, 2, 3), grid.Get(New(3, 2, 1))+1)
+	})
+	if allocs != 0 {
+		t.Errorf("Get/Set allocs = %v, want 0", allocs)
+	}
+}
+
+func BenchmarkDense_Get_3D(b *testing.B) {
+	grid := NewDense[float64](New(0, 0, 0), New(63, 63, 63))
+
+	for i := 0; b.Loop(); i++ {
+		idx := i % 64
+		_ = grid.Get(New(idx, 63-idx, idx/2))
+	}
+}
@@ -22,6 +22,21 @@
 //
 //	bits  0–3:   number of dimensions (max 15)
 //	bits  4–243: Z-order interleaved coordinates (20 bits each)
+//	bit   254:   signed flag, set by [NewSigned]
+//	bit   255:   Hilbert flag, set by [NewHilbert]
+//
+// The flags say how to read the interleaved bits. A signed address stores
+// each coordinate offset by 524,288; methods that read or rewrite
+// coordinates, such as Add, With, Neighbors and Slice, work on the stored
+// values and keep the signed flag on their result. Methods that scale,
+// bucket or measure from the origin (Scale, Quantize, Clamp, Span,
+// MaxCoord, Bounds, FixedCoords) would be skewed by the offset, so they
+// panic on a signed address and TryScale returns [ErrSigned].
+//
+// A Hilbert address stores a curve index rather than coordinates, so every
+// method that reads or rewrites coordinates panics on it, and the Try
+// variants return an error wrapping [ErrHilbert]. Only the raw accessors
+// Coords and DecodeDim read the stored bits of either kind of address.
 //
 // # Constraints
 //
@@ -43,10 +58,15 @@
 //	addr.InRange(ranges...)    // 0 allocs
 //	addr.IsZero()              // 0 allocs
 //	addr.String()              // 0 allocs - uses stack buffer internally
-//
-// Methods that build a new coordinate slice ([Append], [Addr.Slice], [With])
-// perform one allocation each, but the returned [Addr] is always 32 bytes and
-// allocation-free to use as a map key.
+//	addr.With(i, v)            // 0 allocs - rewrites one dimension's bits
+//	addr.Slice(from, to)       // 0 allocs - copies interleaved bit rows
+//	addr.AppendText(buf)       // 0 allocs - when buf has spare capacity
+//	addr.AppendBinary(buf)     // 0 allocs - when buf has spare capacity
+//	addr.AppendBuf(buf, c...)  // 0 allocs - when buf has spare capacity
+//
+// [Addr.Append] builds a new coordinate slice and may perform one
+// allocation; [Addr.AppendBuf] reuses a caller-provided buffer instead. The
+// returned [Addr] is always 32 bytes and allocation-free to use as a map key.
 //
 // # Decoding
 //
@@ -0,0 +1,307 @@
+package lattice
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// binaryVersion identifies the compact binary layout produced by MarshalBinary.
+	binaryVersion = 1
+
+	// binaryHeaderLen is the number of leading bytes holding the version and dimension count.
+	binaryHeaderLen = 2
+
+	// binaryFlagHilbert marks a Hilbert-encoded address in the dimension byte.
+	binaryFlagHilbert = 0x80
+
+	// binaryFlagSigned marks a signed address in the dimension byte.
+	binaryFlagSigned = 0x40
+
+	// binaryFlagsMask selects every encoding flag in the dimension byte.
+	binaryFlagsMask = binaryFlagHilbert | binaryFlagSigned
+
+	// bitsPerByte is the number of bits in a byte.
+	bitsPerByte = 8
+
+	// coordMask selects the low BitsPerCoord bits of a packed coordinate.
+	coordMask = MaxCoordValue
+
+	// maxCoordText is the longest text form of a coordinate plus its separator.
+	maxCoordText = len("1048575,")
+)
+
+var (
+	// ErrVersion is returned when decoding data written with an unknown format version.
+	ErrVersion = errors.New("lattice: unsupported encoding version")
+
+	// ErrMalformed is returned when decoding data that is not a valid encoded address.
+	ErrMalformed = errors.New("lattice: malformed encoding")
+)
+
+// packedLen returns the number of bytes needed to pack dims coordinates.
+func packedLen(dims int) int {
+	return (dims*BitsPerCoord + bitsPerByte - 1) / bitsPerByte
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+//
+// The layout is independent of the in-memory representation:
+//   - byte 0:  format version
+//   - byte 1:  number of dimensions in the low bits; bit 7 is set for
+//     Hilbert-encoded and bit 6 for signed addresses, whose stored values
+//     are written as-is
+//   - bytes 2+: coordinates packed as 20-bit little-endian values
+//
+// A 3-dimension address encodes to 10 bytes, a 12-dimension one to 32.
+func (a Addr) MarshalBinary() ([]byte, error) {
+	return a.AppendBinary(make([]byte, 0, binaryHeaderLen+packedLen(a.Dims())))
+}
+
+// AppendBinary implements encoding.BinaryAppender, appending the
+// MarshalBinary form to b. It allocates nothing when b has enough capacity.
+func (a Addr) AppendBinary(b []byte) ([]byte, error) {
+	coords, dims := a.Coords()
+
+	flags := byte(0)
+	if a.IsHilbert() {
+		flags |= binaryFlagHilbert
+	}
+
+	if a.IsSigned() {
+		flags |= binaryFlagSigned
+	}
+
+	b = append(b, binaryVersion, byte(dims)|flags)
+
+	var (
+		acc   uint64
+		nbits int
+	)
+
+	for i := range dims {
+		acc |= uint64(coords[i]) << nbits //nolint:gosec // coords are in [0, MaxCoordValue]
+		nbits += BitsPerCoord
+
+		for nbits >= bitsPerByte {
+			b = append(b, byte(acc))
+			acc >>= bitsPerByte
+			nbits -= bitsPerByte
+		}
+	}
+
+	if nbits > 0 {
+		b = append(b, byte(acc))
+	}
+
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// It returns an error wrapping ErrVersion or ErrMalformed if data
+// was not produced by MarshalBinary.
+func (a *Addr) UnmarshalBinary(data []byte) error {
+	if len(data) < binaryHeaderLen {
+		return fmt.Errorf("%w: %d bytes is shorter than the header", ErrMalformed, len(data))
+	}
+
+	if data[0] != binaryVersion {
+		return fmt.Errorf("%w: %d", ErrVersion, data[0])
+	}
+
+	flags := data[1] & binaryFlagsMask
+
+	dims := int(data[1] &^ flags)
+	if dims > MaxDimensions {
+		return fmt.Errorf("%w: %d dimensions exceeds max %d", ErrMalformed, dims, MaxDimensions)
+	}
+
+	if want := binaryHeaderLen + packedLen(dims); len(data) != want {
+		return fmt.Errorf("%w: %d dimensions need %d bytes, got %d", ErrMalformed, dims, want, len(data))
+	}
+
+	var (
+		coords Buffer
+		acc    uint64
+		nbits  int
+	)
+
+	pos := binaryHeaderLen
+
+	for i := range dims {
+		for nbits < BitsPerCoord {
+			acc |= uint64(data[pos]) << nbits
+			nbits += bitsPerByte
+			pos++
+		}
+
+		coords[i] = int(acc & coordMask) //nolint:gosec // masked to BitsPerCoord bits
+		acc >>= BitsPerCoord
+		nbits -= BitsPerCoord
+	}
+
+	if acc != 0 {
+		return fmt.Errorf("%w: non-zero padding bits", ErrMalformed)
+	}
+
+	var flagged Addr
+
+	if flags&binaryFlagHilbert != 0 {
+		flagged[flagWord] |= flagHilbert
+	}
+
+	if flags&binaryFlagSigned != 0 {
+		flagged[flagWord] |= flagSigned
+	}
+
+	*a = New(coords[:dims]...).withFlags(flagged)
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder using the MarshalBinary form.
+func (a Addr) GobEncode() ([]byte, error) {
+	return a.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder using the MarshalBinary form.
+// Returns an error wrapping ErrVersion or ErrMalformed on corrupt data.
+func (a *Addr) GobDecode(data []byte) error {
+	return a.UnmarshalBinary(data)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// The address is written as its comma-separated decoded coordinates,
+// e.g. "1,2,3". The empty address encodes to "".
+// The text form cannot mark Hilbert or signed encoding, so such addresses
+// return an error wrapping errors.ErrUnsupported; use MarshalBinary or Key.
+func (a Addr) MarshalText() ([]byte, error) {
+	return a.AppendText(make([]byte, 0, a.Dims()*maxCoordText))
+}
+
+// AppendText implements encoding.TextAppender, appending the MarshalText
+// form to b. It allocates nothing when b has enough capacity, and returns
+// b unchanged with the MarshalText error for flagged addresses.
+func (a Addr) AppendText(b []byte) ([]byte, error) {
+	if a[flagWord]&flagsMask != 0 {
+		return b, fmt.Errorf("%w: text form of flagged %v", errors.ErrUnsupported, a)
+	}
+
+	coords, dims := a.Coords()
+
+	for i := range dims {
+		if i > 0 {
+			b = append(b, ',')
+		}
+
+		b = strconv.AppendInt(b, int64(coords[i]), 10)
+	}
+
+	return b, nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// It accepts the output of MarshalText and returns an error wrapping
+// ErrMalformed, ErrTooManyDims or ErrCoordRange on invalid input.
+func (a *Addr) UnmarshalText(text []byte) error {
+	coords, dims, err := parseCoords(string(text), ',')
+	if err != nil {
+		return err
+	}
+
+	*a = New(coords[:dims]...)
+
+	return nil
+}
+
+// Parse reconstructs an Addr from its String form, e.g. "Addr[1 2 3]",
+// or from a bare space- or comma-separated list such as "1 2 3" or "1,2,3".
+// Parse(a.String()) equals a for every unflagged address, including the
+// empty one. String does not carry the Hilbert or signed flag, so the
+// round trip does not hold for flagged addresses.
+// Returns an error wrapping ErrMalformed, ErrTooManyDims or ErrCoordRange
+// on invalid input.
+func Parse(s string) (Addr, error) {
+	s = trimSpace(s)
+
+	if inner, ok := strings.CutPrefix(s, "Addr["); ok {
+		s, ok = strings.CutSuffix(inner, "]")
+		if !ok {
+			return Addr{}, fmt.Errorf("%w: missing closing bracket in %q", ErrMalformed, "Addr["+inner)
+		}
+	}
+
+	sep := byte(' ')
+	if strings.IndexByte(s, ',') >= 0 {
+		sep = ','
+	}
+
+	coords, dims, err := parseCoords(s, sep)
+	if err != nil {
+		return Addr{}, err
+	}
+
+	return New(coords[:dims]...), nil
+}
+
+// parseCoords splits s on sep and decodes each token as a coordinate.
+// Surrounding spaces are trimmed from each token. When sep is a space,
+// runs of spaces count as a single separator. An empty or all-space s
+// yields zero coordinates.
+func parseCoords(s string, sep byte) (Buffer, int, error) {
+	var coords Buffer
+
+	s = trimSpace(s)
+	if len(s) == 0 {
+		return coords, 0, nil
+	}
+
+	dims := 0
+	start := 0
+
+	for end := 0; end <= len(s); end++ {
+		if end < len(s) && s[end] != sep {
+			continue
+		}
+
+		token := trimSpace(s[start:end])
+		start = end + 1
+
+		if len(token) == 0 && sep == ' ' {
+			continue
+		}
+
+		if dims == MaxDimensions {
+			return coords, 0, fmt.Errorf("%w: max %d dimensions supported", ErrTooManyDims, MaxDimensions)
+		}
+
+		v, err := strconv.Atoi(token)
+		if err != nil {
+			return coords, 0, fmt.Errorf("%w: coord[%d]=%q is not an integer", ErrMalformed, dims, token)
+		}
+
+		if v < 0 || v > MaxCoordValue {
+			return coords, 0, fmt.Errorf("%w: coord[%d]=%d not in [0,%d]", ErrCoordRange, dims, v, MaxCoordValue)
+		}
+
+		coords[dims] = v
+		dims++
+	}
+
+	return coords, dims, nil
+}
+
+// trimSpace removes leading and trailing ASCII spaces and tabs.
+func trimSpace(s string) string {
+	for len(s) > 0 && (s[0] == ' ' || s[0] == '\t') {
+		s = s[1:]
+	}
+
+	for len(s) > 0 && (s[len(s)-1] == ' ' || s[len(s)-1] == '\t') {
+		s = s[:len(s)-1]
+	}
+
+	return s
+}
@@ -0,0 +1,487 @@
+package lattice
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// ============================================================
+// MarshalBinary / UnmarshalBinary
+// ============================================================
+
+func TestBinary_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		coords  []int
+		wantLen int
+	}{
+		{"empty", []int{}, 2},
+		{"one dim", []int{42}, 5},
+		{"three dims", []int{1, 2, 3}, 10},
+		{"max values", []int{MaxCoordValue, MaxCoordValue, MaxCoordValue}, 10},
+		{"max dims", []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, 32},
+		{"max dims max values", []int{
+			MaxCoordValue, MaxCoordValue, MaxCoordValue, MaxCoordValue,
+			MaxCoordValue, MaxCoordValue, MaxCoordValue, MaxCoordValue,
+			MaxCoordValue, MaxCoordValue, MaxCoordValue, MaxCoordValue,
+		}, 32},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			addr := New(testCase.coords...)
+
+			data, err := addr.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+
+			if len(data) != testCase.wantLen {
+				t.Errorf("len(MarshalBinary()) = %d, want %d", len(data), testCase.wantLen)
+			}
+
+			var got Addr
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+
+			if !got.Equal(addr) {
+				t.Errorf("round trip = %v, want %v", got, addr)
+			}
+		})
+	}
+}
+
+func TestBinary_Rejects(t *testing.T) {
+	t.Parallel()
+
+	valid, err := New(1, 2, 3).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"nil", nil, ErrMalformed},
+		{"header only truncated", valid[:1], ErrMalformed},
+		{"truncated payload", valid[:len(valid)-1], ErrMalformed},
+		{"over-long", append(append([]byte{}, valid...), 0), ErrMalformed},
+		{"bad version", append([]byte{99}, valid[1:]...), ErrVersion},
+		{"too many dimensions", []byte{binaryVersion, MaxDimensions + 1}, ErrMalformed},
+		{"non-zero padding", []byte{binaryVersion, 1, 0, 0, 0xF0}, ErrMalformed},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var addr Addr
+
+			err := addr.UnmarshalBinary(testCase.data)
+			if !errors.Is(err, testCase.wantErr) {
+				t.Errorf("UnmarshalBinary() error = %v, want %v", err, testCase.wantErr)
+			}
+		})
+	}
+}
+
+func TestBinary_IndependentOfLayout(t *testing.T) {
+	t.Parallel()
+
+	data, err := New(1, 2).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	// 1 and 2 packed as consecutive 20-bit little-endian values.
+	want := []byte{binaryVersion, 2, 0x01, 0x00, 0x20, 0x00, 0x00}
+	if string(data) != string(want) {
+		t.Errorf("MarshalBinary() = %x, want %x", data, want)
+	}
+}
+
+func TestAppendBinary_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	prefix := []byte("prefix")
+
+	for _, addr := range []Addr{New(), New(7), New(1, 2, 3), NewHilbert(4, 5), NewSigned(-1, 1)} {
+		got, err := addr.AppendBinary(append([]byte{}, prefix...))
+		if err != nil {
+			t.Fatalf("AppendBinary() error = %v", err)
+		}
+
+		if !bytes.HasPrefix(got, prefix) {
+			t.Fatalf("AppendBinary() = %x, lost prefix %x", got, prefix)
+		}
+
+		want, _ := addr.MarshalBinary()
+		if !bytes.Equal(got[len(prefix):], want) {
+			t.Errorf("AppendBinary() appended %x, want %x", got[len(prefix):], want)
+		}
+
+		var decoded Addr
+		if err := decoded.UnmarshalBinary(got[len(prefix):]); err != nil || decoded != addr {
+			t.Errorf("UnmarshalBinary(AppendBinary()) = %v, %v, want %v", decoded, err, addr)
+		}
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestAppendBinary_ZeroAllocs(t *testing.T) {
+	addr := New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)
+	buf := make([]byte, 0, 64)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = addr.AppendBinary(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("AppendBinary allocs = %v, want 0", allocs)
+	}
+}
+
+// ============================================================
+// GobEncode / GobDecode
+// ============================================================
+
+func TestGob_MapRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	maxDims := make([]int, MaxDimensions)
+	for i := range maxDims {
+		maxDims[i] = MaxCoordValue
+	}
+
+	in := map[Addr]float64{
+		New():                   1,
+		New(1, 2, 3):            2.5,
+		New(maxDims...):         -3,
+		New(make([]int, 12)...): 4,
+		New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12): 5,
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	var out map[Addr]float64
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("gob round trip = %v, want %v", out, in)
+	}
+}
+
+func TestGob_DecodeRejectsCorruption(t *testing.T) {
+	t.Parallel()
+
+	data, err := New(1, 2, 3).GobEncode()
+	if err != nil {
+		t.Fatalf("GobEncode() error = %v", err)
+	}
+
+	var addr Addr
+	if err := addr.GobDecode(data[:len(data)-1]); !errors.Is(err, ErrMalformed) {
+		t.Errorf("GobDecode(truncated) error = %v, want %v", err, ErrMalformed)
+	}
+
+	if err := addr.GobDecode(append([]byte{7}, data[1:]...)); !errors.Is(err, ErrVersion) {
+		t.Errorf("GobDecode(bad version) error = %v, want %v", err, ErrVersion)
+	}
+}
+
+// ============================================================
+// MarshalText / UnmarshalText
+// ============================================================
+
+func TestText_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		coords []int
+		want   string
+	}{
+		{"empty", []int{}, ""},
+		{"one dim", []int{7}, "7"},
+		{"three dims", []int{1, 2, 3}, "1,2,3"},
+		{"max value", []int{0, MaxCoordValue}, "0,1048575"},
+		{"max dims", []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, "1,2,3,4,5,6,7,8,9,10,11,12"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			addr := New(testCase.coords...)
+
+			text, err := addr.MarshalText()
+			if err != nil {
+				t.Fatalf("MarshalText() error = %v", err)
+			}
+
+			if string(text) != testCase.want {
+				t.Errorf("MarshalText() = %q, want %q", text, testCase.want)
+			}
+
+			var got Addr
+			if err := got.UnmarshalText(text); err != nil {
+				t.Fatalf("UnmarshalText() error = %v", err)
+			}
+
+			if !got.Equal(addr) {
+				t.Errorf("round trip = %v, want %v", got, addr)
+			}
+		})
+	}
+}
+
+func TestText_EmptyAddr(t *testing.T) {
+	t.Parallel()
+
+	text, err := New().MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText() error = %v", err)
+	}
+
+	if len(text) != 0 {
+		t.Errorf("MarshalText() = %q, want empty", text)
+	}
+
+	got := New(1, 2)
+	if err := got.UnmarshalText([]byte("")); err != nil {
+		t.Fatalf("UnmarshalText() error = %v", err)
+	}
+
+	if !got.Equal(New()) {
+		t.Errorf("UnmarshalText(\"\") = %v, want %v", got, New())
+	}
+}
+
+func TestText_Rejects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		text    string
+		wantErr error
+	}{
+		{"not a number", "1,x,3", ErrMalformed},
+		{"empty token", "1,,3", ErrMalformed},
+		{"trailing comma", "1,2,", ErrMalformed},
+		{"negative", "1,-2", ErrCoordRange},
+		{"too large", "1,1048576", ErrCoordRange},
+		{"too many dims", "1,2,3,4,5,6,7,8,9,10,11,12,13", ErrTooManyDims},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var addr Addr
+
+			err := addr.UnmarshalText([]byte(testCase.text))
+			if !errors.Is(err, testCase.wantErr) {
+				t.Errorf("UnmarshalText(%q) error = %v, want %v", testCase.text, err, testCase.wantErr)
+			}
+		})
+	}
+}
+
+func TestText_JSON(t *testing.T) {
+	t.Parallel()
+
+	type record struct {
+		At    Addr            `json:"at"`
+		Cells map[Addr]string `json:"cells"`
+	}
+
+	in := record{
+		At:    New(1, 2, 3),
+		Cells: map[Addr]string{New(4, 5): "a"},
+	}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	want := `{"at":"1,2,3","cells":{"4,5":"a"}}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	var out record
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	if !out.At.Equal(in.At) || out.Cells[New(4, 5)] != "a" {
+		t.Errorf("json round trip = %+v, want %+v", out, in)
+	}
+}
+
+func TestAppendText_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, addr := range []Addr{New(), New(7), New(1, 2, 3), New(0, MaxCoordValue)} {
+		got, err := addr.AppendText([]byte("cell="))
+		if err != nil {
+			t.Fatalf("AppendText() error = %v", err)
+		}
+
+		want, _ := addr.MarshalText()
+		if string(got) != "cell="+string(want) {
+			t.Errorf("AppendText() = %q, want %q", got, "cell="+string(want))
+		}
+
+		var decoded Addr
+		if err := decoded.UnmarshalText(got[len("cell="):]); err != nil || decoded != addr {
+			t.Errorf("UnmarshalText(AppendText()) = %v, %v, want %v", decoded, err, addr)
+		}
+	}
+}
+
+func TestAppendText_FlaggedLeavesBuffer(t *testing.T) {
+	t.Parallel()
+
+	got, err := NewSigned(-1).AppendText([]byte("x"))
+	if !errors.Is(err, errors.ErrUnsupported) || string(got) != "x" {
+		t.Errorf("AppendText() = %q, %v, want %q, %v", got, err, "x", errors.ErrUnsupported)
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestAppendText_ZeroAllocs(t *testing.T) {
+	addr := New(MaxCoordValue, 2, 3)
+	buf := make([]byte, 0, 64)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf, _ = addr.AppendText(buf[:0])
+	})
+	if allocs != 0 {
+		t.Errorf("AppendText allocs = %v, want 0", allocs)
+	}
+}
+
+func BenchmarkAppendText_3D(b *testing.B) {
+	addr := New(123456, 7890, 42)
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		buf, _ = addr.AppendText(buf[:0])
+	}
+}
+
+func BenchmarkAppendBinary_3D(b *testing.B) {
+	addr := New(123456, 7890, 42)
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		buf, _ = addr.AppendBinary(buf[:0])
+	}
+}
+
+// ============================================================
+// Parse
+// ============================================================
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  Addr
+	}{
+		{"Addr[]", New()},
+		{"", New()},
+		{"Addr[1 2 3]", New(1, 2, 3)},
+		{"1 2 3", New(1, 2, 3)},
+		{"1,2,3", New(1, 2, 3)},
+		{" 1, 2, 3 ", New(1, 2, 3)},
+		{"1   2  3", New(1, 2, 3)},
+		{"Addr[0 1048575]", New(0, MaxCoordValue)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Parse(testCase.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", testCase.input, err)
+			}
+
+			if !got.Equal(testCase.want) {
+				t.Errorf("Parse(%q) = %v, want %v", testCase.input, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestParse_StringRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	addrs := []Addr{
+		New(),
+		New(0),
+		New(1, 2, 3),
+		New(MaxCoordValue, 0, MaxCoordValue),
+		New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12),
+	}
+
+	for _, addr := range addrs {
+		got, err := Parse(addr.String())
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", addr.String(), err)
+		}
+
+		if !got.Equal(addr) {
+			t.Errorf("Parse(%q) = %v, want %v", addr.String(), got, addr)
+		}
+	}
+}
+
+func TestParse_Rejects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input   string
+		wantErr error
+	}{
+		{"Addr[1 2", ErrMalformed},
+		{"Addr[1 x 3]", ErrMalformed},
+		{"1,two", ErrMalformed},
+		{"1 -2 3", ErrCoordRange},
+		{"1048576", ErrCoordRange},
+		{"1 2 3 4 5 6 7 8 9 10 11 12 13", ErrTooManyDims},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.input, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := Parse(testCase.input); !errors.Is(err, testCase.wantErr) {
+				t.Errorf("Parse(%q) error = %v, want %v", testCase.input, err, testCase.wantErr)
+			}
+		})
+	}
+}
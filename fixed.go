@@ -0,0 +1,55 @@
+package lattice
+
+import (
+	"fmt"
+	"math"
+)
+
+// NewFixed creates an Addr from fixed-point float coordinates: each value is
+// multiplied by scale and rounded half to even (math.RoundToEven), so
+// NewFixed(1024, 0.5) stores 512 and values exactly halfway between two
+// lattice points go to the even one.
+// Panics if scale <= 0, if more than MaxDimensions coordinates are provided,
+// or if any value is NaN or rounds outside [0, MaxCoordValue].
+func NewFixed(scale int, coords ...float64) Addr {
+	if scale <= 0 {
+		panic(fmt.Sprintf("lattice: fixed-point scale %d must be positive", scale))
+	}
+
+	if len(coords) > MaxDimensions {
+		panic(fmt.Sprintf("lattice: max %d dimensions supported", MaxDimensions))
+	}
+
+	var ints Buffer
+
+	for i, f := range coords {
+		v := math.RoundToEven(f * float64(scale))
+		if !(v >= 0 && v <= MaxCoordValue) {
+			panic(fmt.Sprintf("lattice: coord[%d]=%g scaled by %d out of range [0,%d]", i, f, scale, MaxCoordValue))
+		}
+
+		ints[i] = int(v)
+	}
+
+	return New(ints[:len(coords)]...)
+}
+
+// FixedCoords returns the coordinates divided by scale, the inverse of
+// NewFixed up to its rounding.
+// Panics if scale <= 0 or a is signed or Hilbert-encoded.
+func (a Addr) FixedCoords(scale int) []float64 {
+	if scale <= 0 {
+		panic(fmt.Sprintf("lattice: fixed-point scale %d must be positive", scale))
+	}
+
+	a.mustPlain()
+
+	coords, dims := a.Coords()
+	out := make([]float64, dims)
+
+	for i := range dims {
+		out[i] = float64(coords[i]) / float64(scale)
+	}
+
+	return out
+}
@@ -0,0 +1,96 @@
+package lattice_test
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/aclivo/lattice"
+)
+
+// ============================================================
+// NewFixed / FixedCoords
+// ============================================================
+
+func TestNewFixed(t *testing.T) {
+	t.Parallel()
+
+	const scale = 1 << 10
+
+	tests := []struct {
+		name   string
+		coords []float64
+		want   lattice.Addr
+	}{
+		{"zero", []float64{0.0}, lattice.New(0)},
+		{"half", []float64{0.5}, lattice.New(512)},
+		{"near one", []float64{1023.0 / 1024}, lattice.New(1023)},
+		{"just below one rounds up", []float64{0.9999}, lattice.New(1024)},
+		{"half to even down", []float64{2.5 / 1024}, lattice.New(2)},
+		{"half to even up", []float64{3.5 / 1024}, lattice.New(4)},
+		{"negative zero", []float64{math.Copysign(0, -1)}, lattice.New(0)},
+		{"tiny negative rounds to zero", []float64{-0.0001}, lattice.New(0)},
+		{"multi", []float64{0, 0.25, 0.5, 0.75}, lattice.New(0, 256, 512, 768)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := lattice.NewFixed(scale, testCase.coords...); got != testCase.want {
+				t.Errorf("NewFixed(%d, %v) = %v, want %v", scale, testCase.coords, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestFixedCoords_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	const scale = lattice.MaxCoordValue
+
+	coords := []float64{0, 0.5, 1, 0.123456}
+	got := lattice.NewFixed(scale, coords...).FixedCoords(scale)
+
+	for i := range coords {
+		if math.Abs(got[i]-coords[i]) > 0.5/scale {
+			t.Errorf("FixedCoords()[%d] = %v, want %v within half a step", i, got[i], coords[i])
+		}
+	}
+
+	if got := lattice.New(512, 1024).FixedCoords(1024); !slices.Equal(got, []float64{0.5, 1}) {
+		t.Errorf("FixedCoords(1024) = %v, want [0.5 1]", got)
+	}
+}
+
+func TestNewFixed_Panics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		fn      func()
+		wantMsg string
+	}{
+		{"rounds above max", func() { lattice.NewFixed(lattice.MaxCoordValue, 1.0000005) }, "lattice: coord[0]=1.0000005 scaled by 1048575 out of range [0,1048575]"},
+		{"negative", func() { lattice.NewFixed(1024, 0.5, -0.5) }, "lattice: coord[1]=-0.5 scaled by 1024 out of range [0,1048575]"},
+		{"NaN", func() { lattice.NewFixed(1024, math.NaN()) }, "lattice: coord[0]=NaN scaled by 1024 out of range [0,1048575]"},
+		{"infinity", func() { lattice.NewFixed(1024, math.Inf(1)) }, "lattice: coord[0]=+Inf scaled by 1024 out of range [0,1048575]"},
+		{"zero scale", func() { lattice.NewFixed(0, 0.5) }, "lattice: fixed-point scale 0 must be positive"},
+		{"inverse zero scale", func() { lattice.New(1).FixedCoords(-1) }, "lattice: fixed-point scale -1 must be positive"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != testCase.wantMsg {
+					t.Errorf("panic message = %q, want %q", got, testCase.wantMsg)
+				}
+			}()
+
+			testCase.fn()
+		})
+	}
+}
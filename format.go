@@ -0,0 +1,109 @@
+package lattice
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Format implements fmt.Formatter:
+//   - %v, %s: the String form e.g. "Addr[1 2 3]"
+//   - %+v:    the String form with the dimension count e.g. "Addr(3)[1 2 3]"
+//   - %#v:    a Go expression e.g. "lattice.New(1, 2, 3)", using NewHilbert
+//     or NewSigned for addresses they built
+//   - %q:     the String form, quoted
+//   - %d, %x, %X, %o, %b: the bare coordinate list in that base e.g. "[a 14 1e]";
+//     flags and width apply to each coordinate as they would for a []int
+//
+// Every verb shows the same coordinates: for Hilbert and signed addresses
+// they are decoded, as HilbertCoords and SignedCoords return them, rather
+// than the stored bits.
+// Width and the '-' flag pad the whole result for %v, %s and %q.
+func (a Addr) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		switch {
+		case f.Flag('#'):
+			writePadded(f, a.goString())
+		case f.Flag('+'):
+			writePadded(f, fmt.Sprintf("Addr(%d)%s", a.Dims(), strings.TrimPrefix(a.String(), "Addr")))
+		default:
+			writePadded(f, a.String())
+		}
+	case 's':
+		writePadded(f, a.String())
+	case 'q':
+		writePadded(f, strconv.Quote(a.String()))
+	case 'd', 'x', 'X', 'o', 'O', 'b':
+		coords, dims := a.displayCoords()
+
+		fmt.Fprintf(f, fmt.FormatString(f, verb), coords[:dims])
+	default:
+		fmt.Fprintf(f, "%%!%c(lattice.Addr=%s)", verb, a.String())
+	}
+}
+
+// displayCoords returns the coordinates every Format verb shows: decoded
+// for Hilbert and signed addresses, stored values otherwise.
+func (a Addr) displayCoords() (Buffer, int) {
+	switch {
+	case a.IsHilbert():
+		return a.HilbertCoords()
+	case a.IsSigned():
+		return a.SignedCoords()
+	default:
+		return a.Coords()
+	}
+}
+
+// goString returns the %#v form of a.
+func (a Addr) goString() string {
+	name := "New"
+
+	switch {
+	case a.IsHilbert():
+		name = "NewHilbert"
+	case a.IsSigned():
+		name = "NewSigned"
+	}
+
+	coords, dims := a.displayCoords()
+
+	var b strings.Builder
+
+	b.WriteString("lattice.")
+	b.WriteString(name)
+	b.WriteByte('(')
+
+	for i := range dims {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+
+		b.WriteString(strconv.Itoa(coords[i]))
+	}
+
+	b.WriteByte(')')
+
+	return b.String()
+}
+
+// writePadded writes s to f, honoring the width and '-' flag.
+func writePadded(f fmt.State, s string) {
+	width, ok := f.Width()
+	if !ok || width <= len(s) {
+		io.WriteString(f, s) //nolint:errcheck // fmt.State writes cannot be reported
+
+		return
+	}
+
+	pad := strings.Repeat(" ", width-len(s))
+	if f.Flag('-') {
+		s += pad
+	} else {
+		s = pad + s
+	}
+
+	io.WriteString(f, s) //nolint:errcheck // fmt.State writes cannot be reported
+}
@@ -0,0 +1,72 @@
+package lattice_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aclivo/lattice"
+)
+
+// ============================================================
+// Format
+// ============================================================
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	addr := lattice.New(10, 20, 255)
+
+	tests := []struct {
+		format string
+		addr   lattice.Addr
+		want   string
+	}{
+		{"%v", addr, "Addr[10 20 255]"},
+		{"%s", addr, "Addr[10 20 255]"},
+		{"%+v", addr, "Addr(3)[10 20 255]"},
+		{"%#v", addr, "lattice.New(10, 20, 255)"},
+		{"%#v", lattice.New(), "lattice.New()"},
+		{"%#v", lattice.NewHilbert(3, 4), "lattice.NewHilbert(3, 4)"},
+		{"%#v", lattice.NewSigned(-3, 4), "lattice.NewSigned(-3, 4)"},
+		{"%v", lattice.NewHilbert(3, 4), "Addr[3 4]"},
+		{"%d", lattice.NewHilbert(3, 4), "[3 4]"},
+		{"%v", lattice.NewSigned(-3, 4), "Addr[-3 4]"},
+		{"%+v", lattice.NewSigned(-3, 4), "Addr(2)[-3 4]"},
+		{"%q", addr, `"Addr[10 20 255]"`},
+		{"%d", addr, "[10 20 255]"},
+		{"%x", addr, "[a 14 ff]"},
+		{"%X", addr, "[A 14 FF]"},
+		{"%#x", addr, "[0xa 0x14 0xff]"},
+		{"%04x", addr, "[000a 0014 00ff]"},
+		{"%o", addr, "[12 24 377]"},
+		{"%b", lattice.New(5), "[101]"},
+		{"%d", lattice.New(), "[]"},
+		{"%12v", lattice.New(1, 2), "   Addr[1 2]"},
+		{"%-10v|", lattice.New(1), "Addr[1]   |"},
+		{"%z", lattice.New(1), "%!z(lattice.Addr=Addr[1])"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.format, func(t *testing.T) {
+			t.Parallel()
+
+			if got := fmt.Sprintf(testCase.format, testCase.addr); got != testCase.want {
+				t.Errorf("Sprintf(%q, %v) = %q, want %q", testCase.format, testCase.addr.String(), got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestFormat_MatchesString(t *testing.T) {
+	t.Parallel()
+
+	for _, addr := range []lattice.Addr{lattice.New(), lattice.New(0), lattice.New(1, 2, 3), lattice.New(lattice.MaxCoordValue, 0), lattice.NewHilbert(3, 4), lattice.NewSigned(-3, 4)} {
+		if got := fmt.Sprint(addr); got != addr.String() {
+			t.Errorf("Sprint(%s) = %q, want %q", addr.String(), got, addr.String())
+		}
+
+		if got := fmt.Sprintf("%v", []lattice.Addr{addr}); got != "["+addr.String()+"]" {
+			t.Errorf("Sprintf(%%v, []Addr) = %q", got)
+		}
+	}
+}
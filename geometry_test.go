@@ -0,0 +1,950 @@
+package lattice_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/aclivo/lattice"
+)
+
+// ============================================================
+// Neighbors
+// ============================================================
+
+func TestNeighbors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		addr lattice.Addr
+		want []lattice.Addr
+	}{
+		{"empty", lattice.New(), []lattice.Addr{}},
+		{"interior 1D", lattice.New(5), []lattice.Addr{lattice.New(4), lattice.New(6)}},
+		{
+			"interior 2D",
+			lattice.New(5, 7),
+			[]lattice.Addr{lattice.New(4, 7), lattice.New(6, 7), lattice.New(5, 6), lattice.New(5, 8)},
+		},
+		{
+			"origin corner",
+			lattice.New(0, 0),
+			[]lattice.Addr{lattice.New(1, 0), lattice.New(0, 1)},
+		},
+		{
+			"max corner",
+			lattice.New(lattice.MaxCoordValue, lattice.MaxCoordValue),
+			[]lattice.Addr{lattice.New(lattice.MaxCoordValue-1, lattice.MaxCoordValue), lattice.New(lattice.MaxCoordValue, lattice.MaxCoordValue-1)},
+		},
+		{
+			"mixed edges",
+			lattice.New(0, 3, lattice.MaxCoordValue),
+			[]lattice.Addr{lattice.New(1, 3, lattice.MaxCoordValue), lattice.New(0, 2, lattice.MaxCoordValue), lattice.New(0, 4, lattice.MaxCoordValue), lattice.New(0, 3, lattice.MaxCoordValue-1)},
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := testCase.addr.Neighbors(nil)
+			if len(got) == 0 && len(testCase.want) == 0 {
+				return
+			}
+
+			if !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("Neighbors() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestNeighbors_ReusesBuffer(t *testing.T) {
+	buf := make([]lattice.Addr, 0, 2*lattice.MaxDimensions)
+	buf = append(buf, lattice.New(9, 9, 9))
+
+	got := lattice.New(1, 1, 1).Neighbors(buf)
+	if len(got) != 6 {
+		t.Fatalf("len(Neighbors()) = %d, want 6", len(got))
+	}
+
+	if &got[0] != &buf[:1][0] {
+		t.Error("expected Neighbors to reuse buf's backing array")
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { _ = lattice.New(1, 1, 1).Neighbors(buf) }); allocs != 0 {
+		t.Errorf("Neighbors() allocs = %v, want 0", allocs)
+	}
+}
+
+// ============================================================
+// StepWrap / NeighborsWrap
+// ============================================================
+
+func TestStepWrap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name                string
+		a                   lattice.Addr
+		dim, delta, modulus int
+		want                lattice.Addr
+	}{
+		{"interior", lattice.New(5, 5), 0, 1, 10, lattice.New(6, 5)},
+		{"wrap high edge", lattice.New(9, 4), 0, 1, 10, lattice.New(0, 4)},
+		{"wrap low edge", lattice.New(0, 4), 0, -1, 10, lattice.New(9, 4)},
+		{"large positive delta", lattice.New(3, 4), 1, 23, 10, lattice.New(3, 7)},
+		{"large negative delta", lattice.New(3, 4), 1, -25, 10, lattice.New(3, 9)},
+		{"full lap", lattice.New(3, 4), 1, 10, 10, lattice.New(3, 4)},
+		{"max modulus", lattice.New(lattice.MaxCoordValue), 0, 1, lattice.MaxCoordValue + 1, lattice.New(0)},
+		{"coord beyond modulus", lattice.New(15), 0, 0, 10, lattice.New(5)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := testCase.a.StepWrap(testCase.dim, testCase.delta, testCase.modulus)
+			if got != testCase.want {
+				t.Errorf("%v.StepWrap(%d, %d, %d) = %v, want %v",
+					testCase.a, testCase.dim, testCase.delta, testCase.modulus, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestNeighborsWrap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		a       lattice.Addr
+		modulus int
+		want    []lattice.Addr
+	}{
+		{"interior", lattice.New(5, 5), 10, []lattice.Addr{lattice.New(4, 5), lattice.New(6, 5), lattice.New(5, 4), lattice.New(5, 6)}},
+		{"origin corner", lattice.New(0, 0), 10, []lattice.Addr{lattice.New(9, 0), lattice.New(1, 0), lattice.New(0, 9), lattice.New(0, 1)}},
+		{"far corner", lattice.New(9, 9), 10, []lattice.Addr{lattice.New(8, 9), lattice.New(0, 9), lattice.New(9, 8), lattice.New(9, 0)}},
+		{"modulus 2", lattice.New(0, 1), 2, []lattice.Addr{lattice.New(1, 1), lattice.New(0, 0)}},
+		{"modulus 1", lattice.New(0, 0), 1, []lattice.Addr{}},
+		{"empty", lattice.New(), 10, []lattice.Addr{}},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := testCase.a.NeighborsWrap(testCase.modulus, make([]lattice.Addr, 0, 8))
+			if !slices.Equal(got, testCase.want) {
+				t.Errorf("%v.NeighborsWrap(%d) = %v, want %v", testCase.a, testCase.modulus, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestNeighborsWrap_MatchesNeighborsInside(t *testing.T) {
+	t.Parallel()
+
+	a := lattice.New(3, 4, 5)
+
+	if got, want := a.NeighborsWrap(100, nil), a.Neighbors(nil); !slices.Equal(got, want) {
+		t.Errorf("NeighborsWrap = %v, Neighbors = %v", got, want)
+	}
+}
+
+func TestStepWrap_Panics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		f    func()
+		want string
+	}{
+		{"zero modulus", func() { lattice.New(1).StepWrap(0, 1, 0) }, "lattice: wrap modulus 0 out of range (0,1048576]"},
+		{"modulus too large", func() { lattice.New(1).NeighborsWrap(lattice.MaxCoordValue+2, nil) }, "lattice: wrap modulus 1048577 out of range (0,1048576]"},
+		{"bad dim", func() { lattice.New(1).StepWrap(1, 1, 10) }, "lattice: dimension index 1 out of range [0:1]"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != testCase.want {
+					t.Errorf("panic message = %q, want %q", got, testCase.want)
+				}
+			}()
+
+			testCase.f()
+		})
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestNeighborsWrap_ZeroAllocs(t *testing.T) {
+	buf := make([]lattice.Addr, 0, 6)
+
+	if allocs := testing.AllocsPerRun(100, func() { _ = lattice.New(0, 5, 9).NeighborsWrap(10, buf) }); allocs != 0 {
+		t.Errorf("NeighborsWrap() allocs = %v, want 0", allocs)
+	}
+}
+
+// ============================================================
+// MooreNeighbors
+// ============================================================
+
+func TestMooreNeighbors_Counts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		addr lattice.Addr
+		want int
+	}{
+		{"empty", lattice.New(), 0},
+		{"1D interior", lattice.New(5), 2},
+		{"2D interior", lattice.New(5, 5), 8},
+		{"3D interior", lattice.New(5, 5, 5), 26},
+		{"4D interior", lattice.New(5, 5, 5, 5), 80},
+		{"2D origin corner", lattice.New(0, 0), 3},
+		{"2D edge", lattice.New(0, 5), 5},
+		{"2D max corner", lattice.New(lattice.MaxCoordValue, lattice.MaxCoordValue), 3},
+		{"3D origin corner", lattice.New(0, 0, 0), 7},
+		{"3D edge", lattice.New(0, 0, 5), 11},
+		{"3D face", lattice.New(0, 5, 5), 17},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := testCase.addr.MooreNeighbors(nil)
+			if len(got) != testCase.want {
+				t.Errorf("len(MooreNeighbors()) = %d, want %d", len(got), testCase.want)
+			}
+
+			seen := make(map[lattice.Addr]bool, len(got))
+
+			for _, n := range got {
+				if seen[n] {
+					t.Errorf("duplicate neighbor %v", n)
+				}
+
+				seen[n] = true
+
+				if n == testCase.addr {
+					t.Errorf("neighborhood includes the cell itself")
+				}
+
+				if !isMooreAdjacent(n, testCase.addr) {
+					t.Errorf("neighbor %v is not adjacent to %v", n, testCase.addr)
+				}
+			}
+		})
+	}
+}
+
+func TestMooreNeighbors_Order2D(t *testing.T) {
+	t.Parallel()
+
+	want := []lattice.Addr{
+		lattice.New(4, 6), lattice.New(4, 7), lattice.New(4, 8),
+		lattice.New(5, 6), lattice.New(5, 8),
+		lattice.New(6, 6), lattice.New(6, 7), lattice.New(6, 8),
+	}
+
+	if got := lattice.New(5, 7).MooreNeighbors(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("MooreNeighbors() = %v, want %v", got, want)
+	}
+}
+
+// isMooreAdjacent reports whether a and b differ by at most 1 in every dimension.
+func isMooreAdjacent(a, b lattice.Addr) bool {
+	aCoords, dims := a.Coords()
+	bCoords, _ := b.Coords()
+
+	for i := range dims {
+		if d := aCoords[i] - bCoords[i]; d < -1 || d > 1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestMooreNeighbors_ReusesBuffer(t *testing.T) {
+	buf := make([]lattice.Addr, 0, 26)
+	addr := lattice.New(5, 5, 5)
+
+	if allocs := testing.AllocsPerRun(100, func() { buf = addr.MooreNeighbors(buf) }); allocs != 0 {
+		t.Errorf("MooreNeighbors() allocs = %v, want 0", allocs)
+	}
+}
+
+// ============================================================
+// ManhattanDistance
+// ============================================================
+
+func TestManhattanDistance(t *testing.T) {
+	t.Parallel()
+
+	maxDims := make([]int, lattice.MaxDimensions)
+	for i := range maxDims {
+		maxDims[i] = lattice.MaxCoordValue
+	}
+
+	tests := []struct {
+		name string
+		a, b lattice.Addr
+		want int
+	}{
+		{"empty", lattice.New(), lattice.New(), 0},
+		{"identical", lattice.New(1, 2, 3), lattice.New(1, 2, 3), 0},
+		{"single axis", lattice.New(1, 2, 3), lattice.New(1, 7, 3), 5},
+		{"all axes", lattice.New(1, 2, 3), lattice.New(4, 0, 6), 8},
+		{"symmetric", lattice.New(4, 0, 6), lattice.New(1, 2, 3), 8},
+		{"max 1D", lattice.New(0), lattice.New(lattice.MaxCoordValue), lattice.MaxCoordValue},
+		{"max 12D", lattice.New(make([]int, lattice.MaxDimensions)...), lattice.New(maxDims...), lattice.MaxDimensions * lattice.MaxCoordValue},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := testCase.a.ManhattanDistance(testCase.b); got != testCase.want {
+				t.Errorf("ManhattanDistance() = %d, want %d", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestManhattanDistance_DimsMismatch(t *testing.T) {
+	t.Parallel()
+
+	if _, err := lattice.New(1, 2).TryManhattanDistance(lattice.New(1, 2, 3)); !errors.Is(err, lattice.ErrDimsMismatch) {
+		t.Errorf("TryManhattanDistance() error = %v, want %v", err, lattice.ErrDimsMismatch)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic with mismatched dimensions")
+		}
+	}()
+
+	lattice.New(1, 2).ManhattanDistance(lattice.New(1, 2, 3))
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestManhattanDistance_ZeroAllocs(t *testing.T) {
+	a, b := lattice.New(1, 2, 3, 4, 5), lattice.New(5, 4, 3, 2, 1)
+
+	if allocs := testing.AllocsPerRun(100, func() { _ = a.ManhattanDistance(b) }); allocs != 0 {
+		t.Errorf("ManhattanDistance() allocs = %v, want 0", allocs)
+	}
+}
+
+// ============================================================
+// ChebyshevDistance / EuclideanDistance
+// ============================================================
+
+func TestDistanceMetrics(t *testing.T) {
+	t.Parallel()
+
+	maxDims := make([]int, lattice.MaxDimensions)
+	for i := range maxDims {
+		maxDims[i] = lattice.MaxCoordValue
+	}
+
+	tests := []struct {
+		name          string
+		a, b          lattice.Addr
+		wantManhattan int
+		wantChebyshev int
+		wantEuclidean float64
+	}{
+		{"identical", lattice.New(1, 2, 3), lattice.New(1, 2, 3), 0, 0, 0},
+		{"single axis", lattice.New(0, 0), lattice.New(0, 7), 7, 7, 7},
+		{"3-4-5 triangle", lattice.New(0, 0), lattice.New(3, 4), 7, 4, 5},
+		{"unit diagonal 3D", lattice.New(0, 0, 0), lattice.New(1, 1, 1), 3, 1, math.Sqrt(3)},
+		{
+			"max 12D",
+			lattice.New(make([]int, lattice.MaxDimensions)...), lattice.New(maxDims...),
+			lattice.MaxDimensions * lattice.MaxCoordValue, lattice.MaxCoordValue, lattice.MaxCoordValue * math.Sqrt(lattice.MaxDimensions),
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			manhattan := testCase.a.ManhattanDistance(testCase.b)
+			chebyshev := testCase.a.ChebyshevDistance(testCase.b)
+			euclidean := testCase.a.EuclideanDistance(testCase.b)
+
+			if manhattan != testCase.wantManhattan {
+				t.Errorf("ManhattanDistance() = %d, want %d", manhattan, testCase.wantManhattan)
+			}
+
+			if chebyshev != testCase.wantChebyshev {
+				t.Errorf("ChebyshevDistance() = %d, want %d", chebyshev, testCase.wantChebyshev)
+			}
+
+			if math.Abs(euclidean-testCase.wantEuclidean) > 1e-9*max(1, testCase.wantEuclidean) {
+				t.Errorf("EuclideanDistance() = %v, want %v", euclidean, testCase.wantEuclidean)
+			}
+
+			// L∞ <= L2 <= L1 holds for every pair.
+			if float64(chebyshev) > euclidean+1e-9 || euclidean > float64(manhattan)+1e-9 {
+				t.Errorf("metric ordering violated: L∞=%d L2=%v L1=%d", chebyshev, euclidean, manhattan)
+			}
+		})
+	}
+}
+
+func TestDistanceMetrics_PanicDimsMismatch(t *testing.T) {
+	t.Parallel()
+
+	metrics := map[string]func(a, b lattice.Addr){
+		"Chebyshev": func(a, b lattice.Addr) { a.ChebyshevDistance(b) },
+		"Euclidean": func(a, b lattice.Addr) { a.EuclideanDistance(b) },
+	}
+
+	for name, metric := range metrics {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic with mismatched dimensions")
+				}
+			}()
+
+			metric(lattice.New(1), lattice.New(1, 2))
+		})
+	}
+}
+
+// ============================================================
+// BoundingBox
+// ============================================================
+
+func TestBoundingBox(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		addrs  []lattice.Addr
+		wantLo lattice.Addr
+		wantHi lattice.Addr
+		wantOK bool
+	}{
+		{"nil", nil, lattice.Addr{}, lattice.Addr{}, false},
+		{"single", []lattice.Addr{lattice.New(4, 5, 6)}, lattice.New(4, 5, 6), lattice.New(4, 5, 6), true},
+		{"two", []lattice.Addr{lattice.New(1, 9), lattice.New(8, 2)}, lattice.New(1, 2), lattice.New(8, 9), true},
+		{
+			"many",
+			[]lattice.Addr{lattice.New(5, 5, 5), lattice.New(0, 7, 3), lattice.New(9, 1, 4), lattice.New(2, 2, lattice.MaxCoordValue)},
+			lattice.New(0, 1, 3), lattice.New(9, 7, lattice.MaxCoordValue), true,
+		},
+		{"empty addresses", []lattice.Addr{lattice.New(), lattice.New()}, lattice.New(), lattice.New(), true},
+		{"mismatched dims", []lattice.Addr{lattice.New(1, 2), lattice.New(1, 2, 3)}, lattice.Addr{}, lattice.Addr{}, false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			lo, hi, ok := lattice.BoundingBox(testCase.addrs)
+			if ok != testCase.wantOK {
+				t.Fatalf("BoundingBox() ok = %v, want %v", ok, testCase.wantOK)
+			}
+
+			if lo != testCase.wantLo || hi != testCase.wantHi {
+				t.Errorf("BoundingBox() = %v, %v, want %v, %v", lo, hi, testCase.wantLo, testCase.wantHi)
+			}
+
+			if !ok {
+				return
+			}
+
+			for _, addr := range testCase.addrs {
+				if !lo.Max(addr).Equal(addr) || !hi.Min(addr).Equal(addr) {
+					t.Errorf("%v lies outside box [%v, %v]", addr, lo, hi)
+				}
+			}
+		})
+	}
+}
+
+// ============================================================
+// Box
+// ============================================================
+
+func TestBox_CountAndUniqueness(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		lo, hi lattice.Addr
+		want   int
+	}{
+		{"empty addresses", lattice.New(), lattice.New(), 1},
+		{"single cell", lattice.New(3, 3), lattice.New(3, 3), 1},
+		{"line", lattice.New(0, 5), lattice.New(9, 5), 10},
+		{"rectangle", lattice.New(1, 2), lattice.New(3, 6), 15},
+		{"cube", lattice.New(0, 0, 0), lattice.New(2, 3, 4), 60},
+		{"at max edge", lattice.New(lattice.MaxCoordValue-1, 0), lattice.New(lattice.MaxCoordValue, 1), 4},
+		{"inverted", lattice.New(5, 5), lattice.New(4, 9), 0},
+		{"inverted last dim", lattice.New(0, 0, 5), lattice.New(3, 3, 4), 0},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			seen := make(map[lattice.Addr]bool)
+			count := 0
+
+			for addr := range lattice.Box(testCase.lo, testCase.hi) {
+				count++
+
+				if seen[addr] {
+					t.Errorf("duplicate cell %v", addr)
+				}
+
+				seen[addr] = true
+
+				if !addr.Max(testCase.lo).Equal(addr) || !addr.Min(testCase.hi).Equal(addr) {
+					t.Errorf("%v lies outside box [%v, %v]", addr, testCase.lo, testCase.hi)
+				}
+			}
+
+			if count != testCase.want {
+				t.Errorf("Box() yielded %d cells, want %d", count, testCase.want)
+			}
+		})
+	}
+}
+
+func TestBox_RowMajorOrder(t *testing.T) {
+	t.Parallel()
+
+	want := []lattice.Addr{lattice.New(0, 0), lattice.New(0, 1), lattice.New(0, 2), lattice.New(1, 0), lattice.New(1, 1), lattice.New(1, 2)}
+	got := make([]lattice.Addr, 0, len(want))
+
+	for addr := range lattice.Box(lattice.New(0, 0), lattice.New(1, 2)) {
+		got = append(got, addr)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Box() = %v, want %v", got, want)
+	}
+}
+
+func TestBox_EarlyBreak(t *testing.T) {
+	t.Parallel()
+
+	count := 0
+
+	for range lattice.Box(lattice.New(0, 0), lattice.New(lattice.MaxCoordValue, lattice.MaxCoordValue)) {
+		count++
+		if count == 5 {
+			break
+		}
+	}
+
+	if count != 5 {
+		t.Errorf("count = %d, want 5", count)
+	}
+}
+
+func TestBox_PanicDimsMismatch(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic with mismatched dimensions")
+		}
+	}()
+
+	lattice.Box(lattice.New(1), lattice.New(1, 2))
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestBox_AllocsIndependentOfSize(t *testing.T) {
+	drain := func(seq func(func(lattice.Addr) bool)) float64 {
+		return testing.AllocsPerRun(10, func() {
+			for range seq { //nolint:revive // draining the iterator
+			}
+		})
+	}
+
+	single := drain(lattice.Box(lattice.New(0, 0, 0), lattice.New(0, 0, 0)))
+	thousand := drain(lattice.Box(lattice.New(0, 0, 0), lattice.New(9, 9, 9)))
+
+	if thousand != single {
+		t.Errorf("allocs for 1000 cells = %v, for 1 cell = %v, want equal", thousand, single)
+	}
+}
+
+// ============================================================
+// Benchmarks
+// ============================================================
+
+func BenchmarkNeighbors_3D(b *testing.B) {
+	addr := lattice.New(10, 20, 30)
+	buf := make([]lattice.Addr, 0, 6)
+
+	for b.Loop() {
+		buf = addr.Neighbors(buf)
+	}
+}
+
+func BenchmarkMooreNeighbors_3D(b *testing.B) {
+	addr := lattice.New(10, 20, 30)
+	buf := make([]lattice.Addr, 0, 26)
+
+	for b.Loop() {
+		buf = addr.MooreNeighbors(buf)
+	}
+}
+
+// ============================================================
+// BoxSize
+// ============================================================
+
+func TestBoxSize(t *testing.T) {
+	t.Parallel()
+
+	maxCorner := lattice.New(lattice.MaxCoordValue, lattice.MaxCoordValue, lattice.MaxCoordValue, lattice.MaxCoordValue)
+
+	// Three full dimensions hold 2^60 cells, which only a 64-bit int counts.
+	side := lattice.MaxCoordValue + 1
+	threeFull, threeFullOK := side*side*side, bits.UintSize == 64
+
+	if !threeFullOK {
+		threeFull = 0
+	}
+
+	tests := []struct {
+		name   string
+		lo, hi lattice.Addr
+		want   int
+		wantOK bool
+	}{
+		{"single cell", lattice.New(3, 4, 5), lattice.New(3, 4, 5), 1, true},
+		{"line", lattice.New(0, 7), lattice.New(9, 7), 10, true},
+		{"box", lattice.New(1, 2, 3), lattice.New(4, 6, 8), 4 * 5 * 6, true},
+		{"empty addresses", lattice.New(), lattice.New(), 1, true},
+		{"three full dims", lattice.New(0, 0, 0), lattice.New(lattice.MaxCoordValue, lattice.MaxCoordValue, lattice.MaxCoordValue), threeFull, threeFullOK},
+		{"overflow", lattice.New(0, 0, 0, 0), maxCorner, 0, false},
+		{"inverted", lattice.New(5, 5), lattice.New(4, 9), 0, false},
+		{"mismatch", lattice.New(1), lattice.New(1, 2), 0, false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := lattice.BoxSize(testCase.lo, testCase.hi)
+			if got != testCase.want || ok != testCase.wantOK {
+				t.Errorf("BoxSize(%v, %v) = %d, %v, want %d, %v", testCase.lo, testCase.hi, got, ok, testCase.want, testCase.wantOK)
+			}
+		})
+	}
+}
+
+func TestBoxSize_MatchesBox(t *testing.T) {
+	t.Parallel()
+
+	lo, hi := lattice.New(2, 0, 5), lattice.New(4, 3, 5)
+
+	n := 0
+
+	for range lattice.Box(lo, hi) {
+		n++
+	}
+
+	if got, ok := lattice.BoxSize(lo, hi); !ok || got != n {
+		t.Errorf("BoxSize() = %d, %v, Box visited %d", got, ok, n)
+	}
+}
+
+// ============================================================
+// Span / MaxCoord
+// ============================================================
+
+func TestSpan(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    lattice.Addr
+		want int
+	}{
+		{"no dims", lattice.New(), 1},
+		{"zero corner", lattice.New(0, 0, 0), 1},
+		{"2D", lattice.New(1, 2), 6},
+		{"3D", lattice.New(9, 9, 9), 1000},
+	}
+
+	// The 2^60 cells of a full 3D box only fit in a 64-bit int.
+	if side := lattice.MaxCoordValue + 1; bits.UintSize == 64 {
+		tests = append(tests, struct {
+			name string
+			a    lattice.Addr
+			want int
+		}{"max 3D", lattice.New(lattice.MaxCoordValue, lattice.MaxCoordValue, lattice.MaxCoordValue), side * side * side})
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := testCase.a.Span(); got != testCase.want {
+				t.Errorf("%v.Span() = %d, want %d", testCase.a, got, testCase.want)
+			}
+
+			coords, dims := testCase.a.Coords()
+			if size, ok := lattice.BoxSize(lattice.New(make([]int, dims)...), lattice.New(coords[:dims]...)); !ok || size != testCase.want {
+				t.Errorf("BoxSize from origin = %d, %v; want %d", size, ok, testCase.want)
+			}
+		})
+	}
+}
+
+func TestSpan_PanicOverflow(t *testing.T) {
+	t.Parallel()
+
+	a := lattice.New(lattice.MaxCoordValue, lattice.MaxCoordValue, lattice.MaxCoordValue, 7)
+
+	defer func() {
+		want := "lattice: span of Addr[1048575 1048575 1048575 7] overflows int"
+		if got := fmt.Sprintf("%v", recover()); got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	a.Span()
+}
+
+func TestMaxCoord(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a    lattice.Addr
+		want int
+	}{
+		{lattice.New(), 0},
+		{lattice.New(0, 0), 0},
+		{lattice.New(3, 9, 1), 9},
+		{lattice.New(5), 5},
+		{lattice.New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, lattice.MaxCoordValue), lattice.MaxCoordValue},
+	}
+
+	for _, testCase := range tests {
+		if got := testCase.a.MaxCoord(); got != testCase.want {
+			t.Errorf("%v.MaxCoord() = %d, want %d", testCase.a, got, testCase.want)
+		}
+	}
+}
+
+func TestBounds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a      lattice.Addr
+		lo, hi int
+		ok     bool
+	}{
+		{lattice.New(), 0, 0, false},
+		{lattice.New(5), 5, 5, true},
+		{lattice.New(0, 0), 0, 0, true},
+		{lattice.New(4, 1, 7), 1, 7, true},
+		{lattice.New(9, 3, 3, 9), 3, 9, true},
+		{lattice.New(lattice.MaxCoordValue, 2, lattice.MaxCoordValue), 2, lattice.MaxCoordValue, true},
+		{lattice.New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 0), 0, 11, true},
+	}
+
+	for _, testCase := range tests {
+		lo, hi, ok := testCase.a.Bounds()
+		if lo != testCase.lo || hi != testCase.hi || ok != testCase.ok {
+			t.Errorf("%v.Bounds() = (%d, %d, %v), want (%d, %d, %v)",
+				testCase.a, lo, hi, ok, testCase.lo, testCase.hi, testCase.ok)
+		}
+
+		if hi != testCase.a.MaxCoord() {
+			t.Errorf("%v.Bounds() hi = %d, MaxCoord() = %d", testCase.a, hi, testCase.a.MaxCoord())
+		}
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestSpanMaxCoord_ZeroAllocs(t *testing.T) {
+	a := lattice.New(10, 20, 30)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = a.Span()
+		_ = a.MaxCoord()
+		_, _, _ = a.Bounds()
+	})
+	if allocs != 0 {
+		t.Errorf("Span/MaxCoord/Bounds allocs = %v, want 0", allocs)
+	}
+}
+
+// ============================================================
+// Lerp / Midpoint
+// ============================================================
+
+func TestLerp(t *testing.T) {
+	t.Parallel()
+
+	a, b := lattice.New(0, 10, 100), lattice.New(10, 0, 101)
+
+	tests := []struct {
+		name string
+		t    float64
+		want lattice.Addr
+	}{
+		{"t=0", 0, a},
+		{"t=1", 1, b},
+		{"t=0.5", 0.5, lattice.New(5, 5, 101)},
+		{"t=0.25", 0.25, lattice.New(3, 8, 100)},
+		{"clamped below", -3, a},
+		{"clamped above", 7, b},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := lattice.Lerp(a, b, testCase.t); got != testCase.want {
+				t.Errorf("Lerp(%v, %v, %v) = %v, want %v", a, b, testCase.t, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestMidpoint(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b, want lattice.Addr
+	}{
+		{lattice.New(0, 0), lattice.New(10, 20), lattice.New(5, 10)},
+		{lattice.New(0, 1), lattice.New(4, 2), lattice.New(2, 2)},
+		{lattice.New(4, 2), lattice.New(0, 1), lattice.New(2, 2)},
+		{lattice.New(7), lattice.New(7), lattice.New(7)},
+		{lattice.New(), lattice.New(), lattice.New()},
+		{lattice.New(0), lattice.New(lattice.MaxCoordValue), lattice.New(lattice.MaxCoordValue/2 + 1)},
+	}
+
+	for _, testCase := range tests {
+		if got := lattice.Midpoint(testCase.a, testCase.b); got != testCase.want {
+			t.Errorf("Midpoint(%v, %v) = %v, want %v", testCase.a, testCase.b, got, testCase.want)
+		}
+	}
+}
+
+func TestLerp_Panics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		fn      func()
+		wantMsg string
+	}{
+		{"mismatch", func() { lattice.Lerp(lattice.New(1), lattice.New(1, 2), 0.5) }, "lattice: dimension mismatch: 1 vs 2 dimensions"},
+		{"NaN", func() { lattice.Lerp(lattice.New(1), lattice.New(2), math.NaN()) }, "lattice: Lerp t is NaN"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != testCase.wantMsg {
+					t.Errorf("panic message = %q, want %q", got, testCase.wantMsg)
+				}
+			}()
+
+			testCase.fn()
+		})
+	}
+}
+
+// ============================================================
+// Line
+// ============================================================
+
+func TestLine_Bresenham2D(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b lattice.Addr
+		want []lattice.Addr
+	}{
+		{"shallow", lattice.New(0, 0), lattice.New(5, 2), []lattice.Addr{lattice.New(0, 0), lattice.New(1, 0), lattice.New(2, 1), lattice.New(3, 1), lattice.New(4, 2), lattice.New(5, 2)}},
+		{"gentle", lattice.New(0, 0), lattice.New(4, 1), []lattice.Addr{lattice.New(0, 0), lattice.New(1, 0), lattice.New(2, 0), lattice.New(3, 1), lattice.New(4, 1)}},
+		{"steep", lattice.New(1, 1), lattice.New(2, 5), []lattice.Addr{lattice.New(1, 1), lattice.New(1, 2), lattice.New(1, 3), lattice.New(2, 4), lattice.New(2, 5)}},
+		{"diagonal", lattice.New(3, 3), lattice.New(0, 0), []lattice.Addr{lattice.New(3, 3), lattice.New(2, 2), lattice.New(1, 1), lattice.New(0, 0)}},
+		{"vertical", lattice.New(2, 4), lattice.New(2, 1), []lattice.Addr{lattice.New(2, 4), lattice.New(2, 3), lattice.New(2, 2), lattice.New(2, 1)}},
+		{"degenerate", lattice.New(7, 7), lattice.New(7, 7), []lattice.Addr{lattice.New(7, 7)}},
+		{"empty addresses", lattice.New(), lattice.New(), []lattice.Addr{lattice.New()}},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := lattice.Line(testCase.a, testCase.b, nil); !reflect.DeepEqual(got, testCase.want) {
+				t.Errorf("Line(%v, %v) = %v, want %v", testCase.a, testCase.b, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestLine_Connected3D(t *testing.T) {
+	t.Parallel()
+
+	a, b := lattice.New(10, 0, 50), lattice.New(0, 7, 20)
+	prefix := []lattice.Addr{lattice.New(1, 1, 1)}
+
+	got := lattice.Line(a, b, prefix)
+
+	if got[0] != prefix[0] {
+		t.Fatal("Line did not append to buf")
+	}
+
+	line := got[1:]
+	if len(line) != a.ChebyshevDistance(b)+1 || line[0] != a || line[len(line)-1] != b {
+		t.Fatalf("Line(%v, %v) = %v", a, b, line)
+	}
+
+	for i := 1; i < len(line); i++ {
+		if d := line[i-1].ChebyshevDistance(line[i]); d != 1 {
+			t.Errorf("step %d from %v to %v moves %d", i, line[i-1], line[i], d)
+		}
+	}
+}
+
+func TestLine_PanicMismatch(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if got, want := fmt.Sprintf("%v", recover()), "lattice: dimension mismatch: 2 vs 1 dimensions"; got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	lattice.Line(lattice.New(1, 2), lattice.New(1), nil)
+}
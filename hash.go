@@ -0,0 +1,75 @@
+package lattice
+
+import "math/bits"
+
+// Multiplicative constants of xxHash64.
+const (
+	hashPrime1 = 0x9E3779B185EBCA87
+	hashPrime2 = 0xC2B2AE3D27D4EB4F
+	hashPrime3 = 0x165667B19E3779F9
+	hashPrime4 = 0x85EBCA77C2B2AE63
+)
+
+// Hash returns a 64-bit hash of the address for open-addressing tables,
+// bloom filters and sharding. The four encoded words are mixed with the
+// xxHash64 round and avalanche steps, so addresses differing in a single
+// coordinate bit land in unrelated buckets.
+//
+// Hash is deterministic and unseeded: it is stable across runs, platforms
+// and releases of this package and may be persisted. It is not resistant
+// to adversarially chosen keys; use hash/maphash over MarshalHash when that
+// matters. Zero allocations.
+func (a Addr) Hash() uint64 {
+	h := uint64(hashPrime4)
+
+	for _, word := range a {
+		h ^= bits.RotateLeft64(word*hashPrime2, 31) * hashPrime1
+		h = bits.RotateLeft64(h, 27)*hashPrime1 + hashPrime4
+	}
+
+	return avalanche(h)
+}
+
+// Fingerprint returns an order-independent 64-bit digest of addrs: the
+// same multiset of addresses, in any order, yields the same value, so
+// repeats count. Like Hash it is unseeded and stable across runs,
+// platforms and releases. Unrelated inputs collide with probability about
+// 2^-64, but the per-address hashes are folded linearly, so colliding sets
+// are easy to construct; do not use it on adversarial input. Zero
+// allocations.
+func Fingerprint(addrs []Addr) uint64 {
+	var sum uint64
+
+	for _, a := range addrs {
+		sum += a.Hash()
+	}
+
+	return avalanche(sum ^ uint64(len(addrs))*hashPrime1) //nolint:gosec // len is never negative
+}
+
+// avalanche is the xxHash64 finalizer: it spreads every input bit across
+// the whole result.
+func avalanche(h uint64) uint64 {
+	h ^= h >> 33
+	h *= hashPrime2
+	h ^= h >> 29
+	h *= hashPrime3
+	h ^= h >> 32
+
+	return h
+}
+
+// MarshalHash returns the canonical byte form of the address for
+// io.Writer-based hashers such as hash/maphash: the MarshalBinary
+// encoding, which holds the dimension count, flags and only the packed
+// coordinates actually in use rather than the raw 32 bytes. Equal
+// addresses yield identical bytes, and distinct valid addresses yield
+// distinct bytes.
+//
+//	var h maphash.Hash
+//	h.Write(a.MarshalHash())
+func (a Addr) MarshalHash() []byte {
+	data, _ := a.MarshalBinary() //nolint:errcheck // MarshalBinary never fails
+
+	return data
+}
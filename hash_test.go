@@ -0,0 +1,213 @@
+package lattice_test
+
+import (
+	"bytes"
+	"hash/maphash"
+	"math/bits"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/aclivo/lattice"
+)
+
+// ============================================================
+// Hash
+// ============================================================
+
+func TestHash_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	a := lattice.New(1, 2, 3)
+	if a.Hash() != lattice.New(1, 2, 3).Hash() {
+		t.Error("equal addresses hash differently")
+	}
+
+	if a.Hash() == lattice.New(1, 2, 4).Hash() || a.Hash() == lattice.New(1, 2).Hash() || a.Hash() == lattice.NewHilbert(1, 2, 3).Hash() {
+		t.Error("distinct addresses share a hash")
+	}
+}
+
+func TestHash_LowCollisions(t *testing.T) {
+	t.Parallel()
+
+	const buckets = 1 << 12
+
+	seen := make(map[uint64]lattice.Addr)
+	counts := make([]int, buckets)
+	cells := 0
+
+	for a := range lattice.Box(lattice.New(0, 0, 0), lattice.New(31, 31, 31)) {
+		h := a.Hash()
+		if prev, ok := seen[h]; ok {
+			t.Fatalf("%v and %v both hash to %#x", prev, a, h)
+		}
+
+		seen[h] = a
+		counts[h%buckets]++
+		cells++
+	}
+
+	// 32768 cells over 4096 buckets average 8 per bucket; a well-mixed hash
+	// stays far below 4× that in its fullest bucket.
+	for i, n := range counts {
+		if n > 4*cells/buckets {
+			t.Errorf("bucket %d holds %d cells, want at most %d", i, n, 4*cells/buckets)
+		}
+	}
+}
+
+func TestHash_Avalanche(t *testing.T) {
+	t.Parallel()
+
+	total, samples := 0, 0
+
+	for x := range 64 {
+		for bit := range lattice.BitsPerCoord {
+			a, b := lattice.New(x, 7), lattice.New(x^1<<bit, 7)
+			total += bits.OnesCount64(a.Hash() ^ b.Hash())
+			samples++
+		}
+	}
+
+	// Flipping one input bit should flip about half of the 64 output bits.
+	if mean := float64(total) / float64(samples); mean < 28 || mean > 36 {
+		t.Errorf("mean flipped output bits = %.1f, want about 32", mean)
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestHash_ZeroAllocs(t *testing.T) {
+	a := lattice.New(1, 2, 3, 4, 5)
+
+	if allocs := testing.AllocsPerRun(100, func() { _ = a.Hash() }); allocs != 0 {
+		t.Errorf("Hash allocs = %v, want 0", allocs)
+	}
+}
+
+// ============================================================
+// MarshalHash
+// ============================================================
+
+func TestMarshalHash_Canonical(t *testing.T) {
+	t.Parallel()
+
+	addrs := []lattice.Addr{
+		lattice.New(),
+		lattice.New(0),
+		lattice.New(0, 0),
+		lattice.New(1, 2, 3),
+		lattice.New(1, 2, 3, 0),
+		lattice.NewHilbert(1, 2, 3),
+		lattice.NewSigned(1, 2, 3),
+		lattice.New(lattice.MaxCoordValue, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, lattice.MaxCoordValue),
+	}
+
+	for _, a := range addrs {
+		for _, b := range addrs {
+			same := bytes.Equal(a.MarshalHash(), b.MarshalHash())
+			if same != a.Equal(b) {
+				t.Errorf("%v, %v: identical bytes = %v, Equal = %v", a, b, same, a.Equal(b))
+			}
+		}
+	}
+}
+
+func TestMarshalHash_Maphash(t *testing.T) {
+	t.Parallel()
+
+	seed := maphash.MakeSeed()
+	sum := func(a lattice.Addr) uint64 {
+		var h maphash.Hash
+
+		h.SetSeed(seed)
+		h.Write(a.MarshalHash()) //nolint:errcheck // maphash.Hash writes never fail
+
+		return h.Sum64()
+	}
+
+	if sum(lattice.New(4, 5)) != sum(lattice.New(4, 5)) {
+		t.Error("equal addresses hash differently under maphash")
+	}
+
+	if sum(lattice.New(4, 5)) == sum(lattice.New(5, 4)) {
+		t.Error("distinct addresses collide under maphash")
+	}
+}
+
+// ============================================================
+// Fingerprint
+// ============================================================
+
+func TestFingerprint_OrderIndependent(t *testing.T) {
+	t.Parallel()
+
+	addrs := slices.Collect(lattice.Box(lattice.New(0, 0), lattice.New(7, 7)))
+	want := lattice.Fingerprint(addrs)
+
+	reversed := slices.Clone(addrs)
+	slices.Reverse(reversed)
+
+	rotated := append(slices.Clone(addrs[17:]), addrs[:17]...)
+
+	shuffled := slices.Clone(addrs)
+	rng := rand.New(rand.NewPCG(1, 2)) //nolint:gosec // deterministic test data
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	for name, perm := range map[string][]lattice.Addr{"reversed": reversed, "rotated": rotated, "shuffled": shuffled} {
+		if got := lattice.Fingerprint(perm); got != want {
+			t.Errorf("%s: Fingerprint = %#x, want %#x", name, got, want)
+		}
+	}
+}
+
+func TestFingerprint_Distinguishes(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := lattice.New(1, 2), lattice.New(3, 4), lattice.New(5, 6)
+
+	inputs := [][]lattice.Addr{
+		nil,
+		{a},
+		{a, a},
+		{a, b},
+		{a, c},
+		{a, b, c},
+		{lattice.New(1, 2, 0)},
+		{lattice.NewHilbert(1, 2)},
+	}
+
+	seen := make(map[uint64]int, len(inputs))
+
+	for i, addrs := range inputs {
+		h := lattice.Fingerprint(addrs)
+		if j, ok := seen[h]; ok {
+			t.Errorf("inputs %v and %v share fingerprint %#x", inputs[j], addrs, h)
+		}
+
+		seen[h] = i
+	}
+
+	if lattice.Fingerprint(nil) != lattice.Fingerprint([]lattice.Addr{}) {
+		t.Error("nil and empty slices fingerprint differently")
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestFingerprint_ZeroAllocs(t *testing.T) {
+	addrs := []lattice.Addr{lattice.New(1, 2), lattice.New(3, 4), lattice.New(5, 6)}
+
+	if allocs := testing.AllocsPerRun(100, func() { _ = lattice.Fingerprint(addrs) }); allocs != 0 {
+		t.Errorf("Fingerprint allocs = %v, want 0", allocs)
+	}
+}
+
+func BenchmarkHash(b *testing.B) {
+	a := lattice.New(100, 200, 300)
+
+	for b.Loop() {
+		_ = a.Hash()
+	}
+}
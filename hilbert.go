@@ -0,0 +1,156 @@
+package lattice
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// NewHilbert creates an Addr from the given coordinates using Hilbert-curve
+// indexing instead of Z-order. Consecutive Hilbert indices are always
+// adjacent cells, so range scans over Hilbert-ordered keys touch fewer
+// distant regions than Morton order.
+//
+// The address uses the same 20-bit/12-dimension budget as New and carries a
+// header flag, so a Hilbert address never equals a Morton address as a map
+// key; NewHilbert() with no coordinates is New(). The two encodings are not
+// interchangeable: decode with HilbertCoords, not Coords or DecodeDim,
+// which read the raw stored index. Methods that read or rewrite coordinates
+// (arithmetic, geometry, ranges, transforms) panic on a Hilbert address, or
+// return an error wrapping ErrHilbert. Next and Prev step along the Hilbert
+// curve.
+// Panics under the same conditions as New.
+func NewHilbert(coords ...int) Addr {
+	x := New(coords...)
+	transpose, dims := x.Coords()
+
+	hilbertTranspose(transpose[:dims])
+	slices.Reverse(transpose[:dims])
+
+	return New(transpose[:dims]...).withFlags(Addr{flagWord: flagHilbert})
+}
+
+// ErrHilbert is returned when a method that reads or rewrites Morton
+// coordinates receives an address built by NewHilbert; the panicking
+// counterparts of such methods panic with its message.
+var ErrHilbert = errors.New("lattice: address is Hilbert-encoded")
+
+// IsHilbert reports whether a was built by NewHilbert.
+func (a Addr) IsHilbert() bool {
+	return a[flagWord]&flagHilbert != 0
+}
+
+// HilbertCoords decodes an address built by NewHilbert, returning its
+// coordinates and their count.
+// Panics if a has coordinates and is not Hilbert-encoded; a 0-dimension
+// address, such as NewHilbert(), decodes to no coordinates.
+func (a Addr) HilbertCoords() (Buffer, int) {
+	if !a.IsHilbert() && a.Dims() > 0 {
+		panic(fmt.Sprintf("lattice: %v is not Hilbert-encoded", a))
+	}
+
+	coords, dims := a.Coords()
+
+	slices.Reverse(coords[:dims])
+	hilbertAxes(coords[:dims])
+
+	return coords, dims
+}
+
+// checkMorton returns an error wrapping ErrHilbert if a is Hilbert-encoded.
+// The stored bits of such an address are a curve index rather than
+// coordinates, so arithmetic, geometry and range methods on them would
+// silently compute on unrelated cells.
+func (a Addr) checkMorton() error {
+	if a.IsHilbert() {
+		return fmt.Errorf("%w: %#v", ErrHilbert, a)
+	}
+
+	return nil
+}
+
+// mustMorton is like checkMorton but panics instead of returning an error.
+func (a Addr) mustMorton() {
+	if err := a.checkMorton(); err != nil {
+		panic(err.Error())
+	}
+}
+
+// The transforms below are John Skilling's ("Programming the Hilbert
+// curve", 2004). They convert between axes and the "transpose" form of the
+// Hilbert index, in which bit k of x[i] is bit k*len(x)+len(x)-1-i of the
+// index. The interleaved region of an Addr stores coordinate i at the lower
+// position within each row, so the transpose is reversed before encoding
+// and the raw interleaved bits read as the Hilbert index itself.
+
+// hilbertTranspose converts axes x in place to the Hilbert transpose.
+func hilbertTranspose(x []int) {
+	n := len(x)
+	if n == 0 {
+		return
+	}
+
+	// Inverse undo.
+	for q := 1 << (BitsPerCoord - 1); q > 1; q >>= 1 {
+		p := q - 1
+
+		for i := range n {
+			if x[i]&q != 0 {
+				x[0] ^= p
+			} else {
+				t := (x[0] ^ x[i]) & p
+				x[0] ^= t
+				x[i] ^= t
+			}
+		}
+	}
+
+	// Gray encode.
+	for i := 1; i < n; i++ {
+		x[i] ^= x[i-1]
+	}
+
+	t := 0
+
+	for q := 1 << (BitsPerCoord - 1); q > 1; q >>= 1 {
+		if x[n-1]&q != 0 {
+			t ^= q - 1
+		}
+	}
+
+	for i := range n {
+		x[i] ^= t
+	}
+}
+
+// hilbertAxes converts the Hilbert transpose x in place back to axes.
+func hilbertAxes(x []int) {
+	n := len(x)
+	if n == 0 {
+		return
+	}
+
+	// Gray decode.
+	t := x[n-1] >> 1
+
+	for i := n - 1; i > 0; i-- {
+		x[i] ^= x[i-1]
+	}
+
+	x[0] ^= t
+
+	// Undo excess work.
+	for q := 2; q != 1<<BitsPerCoord; q <<= 1 {
+		p := q - 1
+
+		for i := n - 1; i >= 0; i-- {
+			if x[i]&q != 0 {
+				x[0] ^= p
+			} else {
+				t := (x[0] ^ x[i]) & p
+				x[0] ^= t
+				x[i] ^= t
+			}
+		}
+	}
+}
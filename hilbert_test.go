@@ -0,0 +1,348 @@
+package lattice
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// compareRaw orders same-dimension addresses by their interleaved bits,
+// i.e. by Morton index for New and by Hilbert index for NewHilbert.
+func compareRaw(a, b Addr) int {
+	for i := flagWord; i >= 0; i-- {
+		if c := cmp.Compare(a[i]&^flagsMask, b[i]&^flagsMask); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// ============================================================
+// NewHilbert / HilbertCoords
+// ============================================================
+
+func TestHilbert_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, coords := range randomCoordSets(50) {
+		addr := NewHilbert(coords...)
+
+		// With no coordinates there is nothing to flag: NewHilbert() is New().
+		if addr.IsHilbert() != (len(coords) > 0) || addr.Dims() != len(coords) {
+			t.Fatalf("NewHilbert(%v): IsHilbert() = %v, Dims() = %d", coords, addr.IsHilbert(), addr.Dims())
+		}
+
+		got, dims := addr.HilbertCoords()
+		if !slices.Equal(got[:dims], coords) {
+			t.Errorf("HilbertCoords(NewHilbert(%v)) = %v", coords, got[:dims])
+		}
+
+		if _, err := FromWords(addr.Words()); err != nil {
+			t.Errorf("FromWords(NewHilbert(%v)) error = %v", coords, err)
+		}
+	}
+}
+
+func TestHilbert_DistinctFromMorton(t *testing.T) {
+	t.Parallel()
+
+	cells := map[Addr]string{}
+
+	for _, coords := range [][]int{{0}, {1, 2}, {0, 0, 0}, {5, 6, 7, 8}} {
+		cells[New(coords...)] = "morton"
+		cells[NewHilbert(coords...)] = "hilbert"
+	}
+
+	if len(cells) != 8 {
+		t.Errorf("map has %d keys, want 8: Hilbert and Morton keys collide", len(cells))
+	}
+
+	if New(1, 2).IsHilbert() {
+		t.Error("New(1, 2).IsHilbert() = true")
+	}
+}
+
+func TestHilbert_Locality(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		lo, hi Addr
+	}{
+		{"1D", New(0), New(255)},
+		{"2D", New(0, 0), New(31, 31)},
+		{"3D", New(0, 0, 0), New(7, 7, 7)},
+		{"4D", New(0, 0, 0, 0), New(3, 3, 3, 3)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var morton, hilbert []Addr
+
+			for a := range Box(testCase.lo, testCase.hi) {
+				coords, dims := a.Coords()
+				morton = append(morton, a)
+				hilbert = append(hilbert, NewHilbert(coords[:dims]...))
+			}
+
+			slices.SortFunc(morton, compareRaw)
+			slices.SortFunc(hilbert, compareRaw)
+
+			mortonJumps, hilbertJumps := 0, 0
+
+			for i := 1; i < len(hilbert); i++ {
+				prev, _ := hilbert[i-1].HilbertCoords()
+				cur, dims := hilbert[i].HilbertCoords()
+
+				jump := New(prev[:dims]...).ManhattanDistance(New(cur[:dims]...))
+				if jump != 1 {
+					t.Errorf("Hilbert step %d jumps %d from %v to %v", i, jump, prev[:dims], cur[:dims])
+				}
+
+				hilbertJumps += jump
+				mortonJumps += morton[i-1].ManhattanDistance(morton[i])
+			}
+
+			if dims := testCase.lo.Dims(); dims > 1 && mortonJumps <= hilbertJumps {
+				t.Errorf("total jump: Morton %d, Hilbert %d; want Hilbert smaller", mortonJumps, hilbertJumps)
+			}
+		})
+	}
+}
+
+func TestHilbertCoords_PanicMorton(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if got, want := fmt.Sprintf("%v", recover()), "lattice: Addr[1 2] is not Hilbert-encoded"; got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	New(1, 2).HilbertCoords()
+}
+
+// ============================================================
+// Morton-only methods
+// ============================================================
+
+func TestHilbert_MortonMethodsPanic(t *testing.T) {
+	t.Parallel()
+
+	h, m := NewHilbert(3, 5), New(1, 1)
+	cube := map[Addr]int{h: 1}
+
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"At", func() { h.At(0) }},
+		{"XY", func() { h.XY() }},
+		{"XYZ", func() { h.XYZ() }},
+		{"XYZW", func() { h.XYZW() }},
+		{"With", func() { h.With(0, 1) }},
+		{"WithCoords", func() { h.WithCoords([2]int{0, 1}) }},
+		{"Incr", func() { h.Incr(0) }},
+		{"Decr", func() { h.Decr(0) }},
+		{"Neighbors", func() { h.Neighbors(nil) }},
+		{"NeighborsWrap", func() { h.NeighborsWrap(8, nil) }},
+		{"MooreNeighbors", func() { h.MooreNeighbors(nil) }},
+		{"StepWrap", func() { h.StepWrap(0, 1, 8) }},
+		{"Add", func() { h.Add(1) }},
+		{"Sub", func() { h.Sub(1) }},
+		{"Min", func() { m.Min(h) }},
+		{"Max", func() { h.Max(m) }},
+		{"Dominates", func() { h.Dominates(m) }},
+		{"StrictlyDominates", func() { m.StrictlyDominates(h) }},
+		{"ManhattanDistance", func() { h.ManhattanDistance(m) }},
+		{"ChebyshevDistance", func() { h.ChebyshevDistance(m) }},
+		{"EuclideanDistance", func() { m.EuclideanDistance(h) }},
+		{"InRange", func() { h.InRange(AddrRange{0, 9}) }},
+		{"InRangeFunc", func() { h.InRangeFunc(nil) }},
+		{"InRangeMode", func() { h.InRangeMode(HalfOpen, AddrRange{0, 9}) }},
+		{"Contains", func() { h.Contains(m) }},
+		{"Contains Hilbert argument", func() { New().Contains(h) }},
+		{"ContainsStrict", func() { New().ContainsStrict(h) }},
+		{"PrefixEqual", func() { h.PrefixEqual(m) }},
+		{"CommonPrefix", func() { m.CommonPrefix(h) }},
+		{"CompareDim", func() { h.CompareDim(m, 0) }},
+		{"Clamp", func() { h.Clamp(AddrRange{0, 1}) }},
+		{"Slice", func() { h.Slice(0, 1) }},
+		{"Parent", func() { h.Parent() }},
+		{"Child", func() { h.Child(1) }},
+		{"Append", func() { h.Append(1) }},
+		{"AppendBuf", func() { h.AppendBuf(nil, 1) }},
+		{"Reverse", func() { h.Reverse() }},
+		{"Swap", func() { h.Swap(0, 1) }},
+		{"Permute", func() { h.Permute([]int{1, 0}) }},
+		{"Map", func() { h.Map(func(_, v int) int { return v }) }},
+		{"Scale", func() { h.Scale(2) }},
+		{"Quantize", func() { h.Quantize(2) }},
+		{"Pad", func() { h.Pad(3, 0) }},
+		{"Truncate", func() { h.Truncate(1) }},
+		{"SetDims", func() { h.SetDims(2, 0) }},
+		{"Span", func() { h.Span() }},
+		{"MaxCoord", func() { h.MaxCoord() }},
+		{"Bounds", func() { h.Bounds() }},
+		{"FixedCoords", func() { h.FixedCoords(10) }},
+		{"BoundingBox", func() { BoundingBox([]Addr{m, h}) }},
+		{"Box", func() { Box(h, h) }},
+		{"Lerp", func() { Lerp(m, h, 0.5) }},
+		{"Line", func() { Line(h, m, nil) }},
+		{"NewDense", func() { NewDense[int](m, h) }},
+		{"Nearest", func() { Nearest(map[Addr]int{m: 1}, h) }},
+		{"KNearest", func() { KNearest(map[Addr]int{m: 1}, h, 1) }},
+		{"RangeScan", func() {
+			for range RangeScan(cube, AddrRange{0, 9}) {
+				break
+			}
+		}},
+		{"SumRange", func() { SumRange(cube) }},
+		{"Dice", func() { Dice(cube) }},
+		{"RollUp", func() { RollUp(cube, 0) }},
+		{"Project", func() { Project(cube, 0) }},
+		{"Translate", func() { Translate(cube, 1) }},
+		{"Flip", func() { Flip(cube, 0, 9) }},
+		{"Downsample", func() { Downsample(cube, 2, func(acc, v int) int { return acc + v }) }},
+	}
+
+	want := "lattice: address is Hilbert-encoded: lattice.NewHilbert(3, 5)"
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != want {
+					t.Errorf("panic message = %q, want %q", got, want)
+				}
+			}()
+
+			testCase.fn()
+		})
+	}
+}
+
+func TestHilbert_TryMethodsReturnErrHilbert(t *testing.T) {
+	t.Parallel()
+
+	h := NewHilbert(3, 5)
+
+	tests := []struct {
+		name string
+		fn   func() (Addr, error)
+	}{
+		{"TryAdd", func() (Addr, error) { return h.TryAdd(1) }},
+		{"TrySub", func() (Addr, error) { return h.TrySub(1) }},
+		{"TryWith", func() (Addr, error) { return h.TryWith(0, 1) }},
+		{"TrySlice", func() (Addr, error) { return h.TrySlice(0, 1) }},
+		{"TryAppend", func() (Addr, error) { return h.TryAppend(1) }},
+		{"TryScale", func() (Addr, error) { return h.TryScale(2) }},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := testCase.fn()
+			if !errors.Is(err, ErrHilbert) {
+				t.Errorf("error = %v, want %v", err, ErrHilbert)
+			}
+
+			if got != h {
+				t.Errorf("result = %v, want the receiver %v", got, h)
+			}
+		})
+	}
+
+	if _, err := New(1, 1).TryManhattanDistance(h); !errors.Is(err, ErrHilbert) {
+		t.Errorf("TryManhattanDistance() error = %v, want %v", err, ErrHilbert)
+	}
+
+	if _, ok := BoxSize(h, h); ok {
+		t.Error("BoxSize(Hilbert) ok = true, want false")
+	}
+
+	if got, err := h.InRangeStrict(AddrRange{0, 9}); !errors.Is(err, ErrHilbert) || got {
+		t.Errorf("InRangeStrict() = %v, %v, want false, %v", got, err, ErrHilbert)
+	}
+
+	if got, err := TryFlip(map[Addr]int{h: 1}, 0, 9); !errors.Is(err, ErrHilbert) || got != nil {
+		t.Errorf("TryFlip() = %v, %v, want nil, %v", got, err, ErrHilbert)
+	}
+
+	if v, ok := h.TryAt(0); ok {
+		t.Errorf("TryAt(0) = %d, true, want ok = false", v)
+	}
+}
+
+func TestHilbert_RawAccessorsAndCurveSteps(t *testing.T) {
+	t.Parallel()
+
+	h := NewHilbert(3, 5)
+
+	coords, dims := h.Coords()
+	if dims != 2 || h.DecodeDim(0) != coords[0] || h.DecodeDim(1) != coords[1] {
+		t.Errorf("raw accessors disagree: Coords() = %v, DecodeDim = %d, %d", coords[:dims], h.DecodeDim(0), h.DecodeDim(1))
+	}
+
+	next, ok := h.Next()
+	if !ok || !next.IsHilbert() {
+		t.Fatalf("Next() = %v, %v, want a Hilbert address", next, ok)
+	}
+
+	// Consecutive Hilbert indices are adjacent cells.
+	hc, _ := h.HilbertCoords()
+	nc, _ := next.HilbertCoords()
+
+	if d := New(hc[:2]...).ManhattanDistance(New(nc[:2]...)); d != 1 {
+		t.Errorf("Next() moved %d cells from (3,5), want 1", d)
+	}
+}
+
+// ============================================================
+// Encodings
+// ============================================================
+
+func TestHilbert_BinaryRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, addr := range []Addr{NewHilbert(7), NewHilbert(1, 2, 3), NewHilbert(make([]int, 12)...)} {
+		data, err := addr.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary() error = %v", err)
+		}
+
+		if data[1]&binaryFlagHilbert == 0 {
+			t.Errorf("MarshalBinary(%v) dims byte %#x lacks Hilbert flag", addr, data[1])
+		}
+
+		var got Addr
+		if err := got.UnmarshalBinary(data); err != nil || got != addr {
+			t.Errorf("UnmarshalBinary() = %v, %v, want %v", got, err, addr)
+		}
+
+		if got, err := ParseKey(addr.Key()); err != nil || got != addr {
+			t.Errorf("ParseKey(Key()) = %v, %v, want %v", got, err, addr)
+		}
+	}
+}
+
+func TestHilbert_TextUnsupported(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewHilbert(1, 2).MarshalText(); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("MarshalText() error = %v, want %v", err, errors.ErrUnsupported)
+	}
+}
+
+func BenchmarkNewHilbert_3D(b *testing.B) {
+	for i := 0; b.Loop(); i++ {
+		_ = NewHilbert(i&MaxCoordValue, (i+1)&MaxCoordValue, (i+2)&MaxCoordValue)
+	}
+}
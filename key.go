@@ -0,0 +1,51 @@
+package lattice
+
+import (
+	"encoding/base32"
+	"fmt"
+	"strings"
+)
+
+// crockford is the Crockford base32 alphabet: digits and upper-case letters
+// without I, L, O and U, so keys avoid ambiguous characters and are URL-safe.
+var crockford = base32.NewEncoding("0123456789ABCDEFGHJKMNPQRSTVWXYZ").WithPadding(base32.NoPadding)
+
+// crockfordAliases maps the lenient input forms Crockford decoding accepts
+// onto the canonical alphabet.
+var crockfordAliases = strings.NewReplacer("I", "1", "L", "1", "O", "0")
+
+// Key returns a compact, URL-safe string for the address: the
+// MarshalBinary form in unpadded Crockford base32. A 3-dimension address
+// yields a 16-character key. Keys are stable across releases because the
+// binary form is versioned.
+func (a Addr) Key() string {
+	data, _ := a.MarshalBinary() //nolint:errcheck // MarshalBinary never fails
+
+	return crockford.EncodeToString(data)
+}
+
+// ParseKey reconstructs an address from Key output. Decoding is
+// case-insensitive and accepts I and L for 1 and O for 0, as Crockford
+// base32 specifies. Each address has exactly one key: input that decodes
+// but is not what Key would produce, such as stray bits in the final
+// symbol, is rejected. Returns an error wrapping ErrMalformed or
+// ErrVersion on invalid input.
+func ParseKey(s string) (Addr, error) {
+	normalized := crockfordAliases.Replace(strings.ToUpper(s))
+
+	data, err := crockford.DecodeString(normalized)
+	if err != nil {
+		return Addr{}, fmt.Errorf("%w: key %q: %w", ErrMalformed, s, err)
+	}
+
+	var addr Addr
+	if err := addr.UnmarshalBinary(data); err != nil {
+		return Addr{}, err
+	}
+
+	if addr.Key() != normalized {
+		return Addr{}, fmt.Errorf("%w: key %q is not canonical", ErrMalformed, s)
+	}
+
+	return addr, nil
+}
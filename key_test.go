@@ -0,0 +1,129 @@
+package lattice_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aclivo/lattice"
+)
+
+// ============================================================
+// Key / ParseKey
+// ============================================================
+
+func TestKey_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	maxDims := make([]int, lattice.MaxDimensions)
+	for i := range maxDims {
+		maxDims[i] = lattice.MaxCoordValue
+	}
+
+	tests := []struct {
+		name    string
+		addr    lattice.Addr
+		wantLen int
+	}{
+		{"empty", lattice.New(), 4},
+		{"three dims", lattice.New(1, 2, 3), 16},
+		{"max 12D", lattice.New(maxDims...), 52},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			key := testCase.addr.Key()
+			if len(key) != testCase.wantLen {
+				t.Errorf("len(Key()) = %d, want %d", len(key), testCase.wantLen)
+			}
+
+			got, err := lattice.ParseKey(key)
+			if err != nil {
+				t.Fatalf("ParseKey(%q) error = %v", key, err)
+			}
+
+			if !got.Equal(testCase.addr) {
+				t.Errorf("ParseKey(%q) = %v, want %v", key, got, testCase.addr)
+			}
+
+			got, err = lattice.ParseKey(strings.ToLower(key))
+			if err != nil || !got.Equal(testCase.addr) {
+				t.Errorf("ParseKey(lower) = %v, %v, want %v", got, err, testCase.addr)
+			}
+		})
+	}
+}
+
+func TestKey_Stable(t *testing.T) {
+	t.Parallel()
+
+	// Keys persisted by earlier releases must keep decoding to the same address.
+	const key = "041G201000006000"
+
+	if got := lattice.New(1, 2, 3).Key(); got != key {
+		t.Errorf("Key() = %q, want %q", got, key)
+	}
+
+	got, err := lattice.ParseKey("O41g2OlOOOOO6OOO")
+	if err != nil || !got.Equal(lattice.New(1, 2, 3)) {
+		t.Errorf("ParseKey(aliased) = %v, %v, want %v", got, err, lattice.New(1, 2, 3))
+	}
+}
+
+func TestParseKey_Rejects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key     string
+		wantErr error
+	}{
+		{"", lattice.ErrMalformed},
+		{"U", lattice.ErrMalformed},
+		{"04-0", lattice.ErrMalformed},
+		{"041G2010000", lattice.ErrMalformed},
+		{"Z41G201000006000", lattice.ErrVersion},
+		{"0401", lattice.ErrMalformed},
+		{"040F", lattice.ErrMalformed},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.key, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := lattice.ParseKey(testCase.key); !errors.Is(err, testCase.wantErr) {
+				t.Errorf("ParseKey(%q) error = %v, want %v", testCase.key, err, testCase.wantErr)
+			}
+		})
+	}
+}
+
+func FuzzParseKey(f *testing.F) {
+	f.Add(lattice.New().Key())
+	f.Add(lattice.New(1, 2, 3).Key())
+	f.Add(lattice.New(lattice.MaxCoordValue, 0, lattice.MaxCoordValue, 0, lattice.MaxCoordValue).Key())
+	f.Add("not a key")
+	f.Add("040F")
+
+	f.Fuzz(func(t *testing.T, key string) {
+		addr, err := lattice.ParseKey(key)
+		if err != nil {
+			return
+		}
+
+		// ParseKey accepts only canonical keys, up to case and aliases.
+		if normalized := strings.NewReplacer("I", "1", "L", "1", "O", "0").Replace(strings.ToUpper(key)); addr.Key() != normalized {
+			t.Errorf("Key(ParseKey(%q)) = %q, want %q", key, addr.Key(), normalized)
+		}
+
+		again, err := lattice.ParseKey(addr.Key())
+		if err != nil {
+			t.Fatalf("ParseKey(%q) error = %v", addr.Key(), err)
+		}
+
+		if !again.Equal(addr) {
+			t.Errorf("ParseKey(Key()) = %v, want %v", again, addr)
+		}
+	})
+}
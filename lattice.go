@@ -1,6 +1,12 @@
 package lattice
 
-import "fmt"
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+)
 
 // Addr is a compact, Z-order encoded multidimensional address.
 // It supports up to 12 dimensions with values ranging from 0 to 1,048,575.
@@ -8,6 +14,8 @@ import "fmt"
 // Bit layout:
 //   - bits 0-3:   number of dimensions (max 15)
 //   - bits 4-243: Z-order interleaved coordinates (20 bits each)
+//   - bit 254:    set for signed addresses (see NewSigned)
+//   - bit 255:    set for Hilbert-encoded addresses (see NewHilbert)
 type Addr [4]uint64
 
 const (
@@ -20,6 +28,11 @@ const (
 	// MaxCoordValue is the maximum value a coordinate can hold (2^20 - 1 = 1,048,575).
 	MaxCoordValue = (1 << BitsPerCoord) - 1
 
+	// MaxEncodableBits is the coordinate bit budget of an Addr: its 256
+	// bits minus the 4-bit dimension header and the 2 encoding flags (250).
+	// MaxDimensions × BitsPerCoord = 240 bits fit within it.
+	MaxEncodableBits = addrBits - dimsBits - flagBits
+
 	// dimsBits is the number of bits used to store the number of dimensions.
 	dimsBits = 4
 
@@ -28,6 +41,37 @@ const (
 
 	// bitsPerWord is the number of bits in a uint64 word.
 	bitsPerWord = 64
+
+	// flagWord is the index of the word holding the encoding flags.
+	flagWord = 3
+
+	// flagHilbert marks an address built by NewHilbert.
+	flagHilbert = 1 << 63
+
+	// flagSigned marks an address built by NewSigned.
+	flagSigned = 1 << 62
+
+	// flagsMask selects every encoding flag in flagWord.
+	flagsMask = flagHilbert | flagSigned
+
+	// flagBits is the number of encoding flag bits.
+	flagBits = 2
+)
+
+var (
+	// ErrTooManyDims is returned when more than MaxDimensions coordinates are supplied.
+	ErrTooManyDims = errors.New("lattice: too many dimensions")
+
+	// ErrCoordRange is returned when a coordinate is outside [0, MaxCoordValue].
+	ErrCoordRange = errors.New("lattice: coordinate out of range")
+
+	// ErrDimsMismatch is returned when an operation receives operands of
+	// incompatible dimensionality.
+	ErrDimsMismatch = errors.New("lattice: dimension mismatch")
+
+	// ErrDimIndex is returned when a dimension index or slice bound is
+	// outside the address's dimensions.
+	ErrDimIndex = errors.New("lattice: dimension index out of range")
 )
 
 // New creates a new Addr from the given coordinates using Z-order encoding.
@@ -38,32 +82,117 @@ func New(coords ...int) Addr {
 		panic(fmt.Sprintf("lattice: max %d dimensions supported", MaxDimensions))
 	}
 
+	return encode(coords)
+}
+
+// New2 is New(x, y) without the variadic slice: the coordinates go
+// straight to the encoder. Panics like New.
+func New2(x, y int) Addr {
+	coords := [...]int{x, y}
+
+	return encode(coords[:])
+}
+
+// New3 is New(x, y, z) without the variadic slice. Panics like New.
+func New3(x, y, z int) Addr {
+	coords := [...]int{x, y, z}
+
+	return encode(coords[:])
+}
+
+// New4 is New(x, y, z, w) without the variadic slice. Panics like New.
+func New4(x, y, z, w int) Addr {
+	coords := [...]int{x, y, z, w}
+
+	return encode(coords[:])
+}
+
+// NewFromSlice is New(coords[from:to]...) for records packed end to end in
+// one slice: the sub-slice is encoded in place, without copying.
+// Panics if from and to do not satisfy 0 <= from <= to <= len(coords), or
+// under the same conditions as New.
+func NewFromSlice(coords []int, from, to int) Addr {
+	if from < 0 || to > len(coords) || from > to {
+		panic(fmt.Sprintf("lattice: slice [%d:%d] out of range [0:%d]", from, to, len(coords)))
+	}
+
+	return New(coords[from:to]...)
+}
+
+// encode validates and encodes at most MaxDimensions coordinates.
+// Panics if any coordinate is out of range [0, MaxCoordValue].
+func encode(coords []int) Addr {
 	for i, v := range coords {
 		if v < 0 || v > MaxCoordValue {
 			panic(fmt.Sprintf("lattice: coord[%d]=%d out of range [0,%d]", i, v, MaxCoordValue))
 		}
 	}
 
-	var addr Addr
+	return interleave(coords)
+}
 
-	addr[0] = uint64(len(coords))
+// NewChecked is like New but returns an error wrapping ErrTooManyDims or
+// ErrCoordRange instead of panicking on invalid coordinates.
+func NewChecked(coords ...int) (Addr, error) {
+	if err := ValidCoords(coords...); err != nil {
+		return Addr{}, err
+	}
 
-	numDims := len(coords)
-	for bitPos := range BitsPerCoord {
-		for dimIdx := range numDims {
-			bit := (coords[dimIdx] >> bitPos) & 1
+	return New(coords...), nil
+}
 
-			encodedBitPos := dimsBits + bitPos*numDims + dimIdx
+// RoundTrips reports whether coords survive encoding with New and decoding
+// with Coords unchanged. It is the package's core contract, exposed so
+// downstream property tests and fuzzers can assert it over their own
+// coordinate distributions. Coordinates New would reject report false
+// instead of panicking. Zero allocations.
+func RoundTrips(coords []int) bool {
+	if ValidCoords(coords...) != nil {
+		return false
+	}
 
-			if bit == 1 {
-				arrayIdx := encodedBitPos / bitsPerWord
-				bitInWord := encodedBitPos % bitsPerWord
-				addr[arrayIdx] |= 1 << bitInWord
-			}
+	got, dims := New(coords...).Coords()
+
+	return slices.Equal(got[:dims], coords)
+}
+
+// ValidCoord reports whether v is a valid coordinate, in [0, MaxCoordValue].
+func ValidCoord(v int) bool {
+	return v >= 0 && v <= MaxCoordValue
+}
+
+// ValidDims reports whether n is a valid dimension count, in [0, MaxDimensions].
+func ValidDims(n int) bool {
+	return n >= 0 && n <= MaxDimensions
+}
+
+// ValidCoords reports why New would reject coords, returning the same error
+// as NewChecked, or nil if they are valid.
+func ValidCoords(coords ...int) error {
+	if !ValidDims(len(coords)) {
+		return fmt.Errorf("%w: %d coordinates, max %d", ErrTooManyDims, len(coords), MaxDimensions)
+	}
+
+	for i, v := range coords {
+		if !ValidCoord(v) {
+			return fmt.Errorf("%w: coord[%d]=%d not in [0,%d]", ErrCoordRange, i, v, MaxCoordValue)
 		}
 	}
 
-	return addr
+	return nil
+}
+
+// MaxCoordsForDims returns how many bits each coordinate of a dims-dimensional
+// Addr actually holds: BitsPerCoord for every valid dimension count, since
+// Addr uses a fixed width rather than splitting MaxEncodableBits. Returns 0
+// if dims is outside [1, MaxDimensions]. Use a Codec for wider coordinates
+// at lower dimension counts.
+func MaxCoordsForDims(dims int) (bitsPerCoord int) {
+	if dims < 1 || dims > MaxDimensions {
+		return 0
+	}
+
+	return BitsPerCoord
 }
 
 // Dims returns the number of dimensions in this address.
@@ -75,23 +204,7 @@ func (a Addr) Dims() int {
 // Use dims to know how many elements are valid.
 // Zero allocations.
 func (a Addr) Coords() (Buffer, int) {
-	var coords Buffer
-
-	dims := a.Dims()
-
-	for bitPos := range BitsPerCoord {
-		for dimIdx := range dims {
-			encodedBitPos := dimsBits + bitPos*dims + dimIdx
-			arrayIdx := encodedBitPos / bitsPerWord
-			bitInWord := encodedBitPos % bitsPerWord
-
-			if (a[arrayIdx]>>bitInWord)&1 == 1 {
-				coords[dimIdx] |= 1 << bitPos
-			}
-		}
-	}
-
-	return coords, dims
+	return deinterleave(a)
 }
 
 // CoordsSlice decodes coordinates into the provided buffer.
@@ -112,9 +225,37 @@ func (a Addr) CoordsSlice(buf []int) []int {
 	return buf
 }
 
+// CoordsAppend appends the decoded coordinates to dst and returns the
+// extended slice, following the append convention: dst grows as needed
+// and no allocation occurs when it has enough spare capacity.
+func (a Addr) CoordsAppend(dst []int) []int {
+	coords, dims := a.Coords()
+
+	return append(dst, coords[:dims]...)
+}
+
+// All returns an iterator over (dimIndex, coordValue) pairs
+// e.g. for i, v := range addr.All() { ... }.
+// Coordinates are decoded once onto the stack and yielded without
+// allocation. Because Addr is a value, the iterator works on a copy and
+// the address cannot change during iteration.
+func (a Addr) All() iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		coords, dims := a.Coords()
+
+		for i := range dims {
+			if !yield(i, coords[i]) {
+				return
+			}
+		}
+	}
+}
+
 // Append returns a new Addr with extra coordinates added
 // e.g. Addr{1,2}.Append(3) → Addr{1,2,3}.
 func (a Addr) Append(coords ...int) Addr {
+	a.mustMorton()
+
 	ac, dims := a.Coords()
 	next := make([]int, dims+len(coords))
 
@@ -124,23 +265,161 @@ func (a Addr) Append(coords ...int) Addr {
 
 	copy(next[dims:], coords)
 
-	return New(next...)
+	return New(next...).withFlags(a)
+}
+
+// TryAppend is like Append but returns an error wrapping ErrTooManyDims,
+// ErrCoordRange or ErrHilbert instead of panicking, with coordinates
+// numbered by their position in the result. On error the receiver is
+// returned unchanged.
+// No allocation takes place.
+func (a Addr) TryAppend(coords ...int) (Addr, error) {
+	if err := a.checkMorton(); err != nil {
+		return a, err
+	}
+
+	buf, dims := a.Coords()
+	if dims+len(coords) > MaxDimensions {
+		return a, fmt.Errorf("%w: %d coordinates, max %d", ErrTooManyDims, dims+len(coords), MaxDimensions)
+	}
+
+	n := dims + copy(buf[dims:], coords)
+	if err := ValidCoords(buf[:n]...); err != nil {
+		return a, err
+	}
+
+	return New(buf[:n]...).withFlags(a), nil
+}
+
+// AppendBuf is like Append but builds the combined coordinates in buf,
+// which is cleared and reused, so nothing is allocated when cap(buf) is
+// at least Dims()+len(coords). A buffer of MaxDimensions always suffices.
+// Panics like Append if the result exceeds MaxDimensions or a coordinate
+// is out of range.
+func (a Addr) AppendBuf(buf []int, coords ...int) Addr {
+	a.mustMorton()
+
+	buf = append(a.CoordsAppend(buf[:0]), coords...)
+
+	return New(buf...).withFlags(a)
+}
+
+// Parent returns the address with the last dimension dropped
+// e.g. Addr{1,2,3}.Parent() → Addr{1,2}.
+// It is equivalent to Slice(0, Dims()-1), so Parent().Contains(a) holds.
+// Panics on an empty address.
+func (a Addr) Parent() Addr {
+	dims := a.Dims()
+	if dims == 0 {
+		panic("lattice: empty address has no parent")
+	}
+
+	return a.Slice(0, dims-1)
+}
+
+// Child returns the address with one coordinate appended
+// e.g. Addr{1,2}.Child(3) → Addr{1,2,3}.
+// Unlike Append it performs no allocation.
+// Panics like New if the result exceeds MaxDimensions or coord is out of range.
+func (a Addr) Child(coord int) Addr {
+	a.mustMorton()
+
+	coords, dims := a.Coords()
+	if dims == MaxDimensions {
+		panic(fmt.Sprintf("lattice: max %d dimensions supported", MaxDimensions))
+	}
+
+	coords[dims] = coord
+
+	return New(coords[:dims+1]...).withFlags(a)
 }
 
 // At returns the coordinate value at a specific dimension
 // e.g. Addr{1,2,3}.At(1) → 2.
+// Panics if dimIdx is out of range [0, Dims()) or a is Hilbert-encoded.
 func (a Addr) At(dimIdx int) int {
-	ac, dims := a.Coords()
+	a.mustMorton()
+
+	return a.DecodeDim(dimIdx)
+}
+
+// TryAt is like At but reports ok=false instead of panicking when dimIdx
+// is out of range [0, Dims()) or a is Hilbert-encoded. Zero allocations.
+func (a Addr) TryAt(dimIdx int) (int, bool) {
+	if a.IsHilbert() || dimIdx < 0 || dimIdx >= a.Dims() {
+		return 0, false
+	}
+
+	return a.DecodeDim(dimIdx), true
+}
+
+// DecodeDim decodes only the coordinate at dimIdx, reading its 20
+// interleaved bits without decoding the other dimensions. Like Coords it
+// reads the stored bits whatever the encoding flags.
+// Panics if dimIdx is out of range [0, Dims()).
+func (a Addr) DecodeDim(dimIdx int) int {
+	dims := a.Dims()
 	if dimIdx < 0 || dimIdx >= dims {
 		panic(fmt.Sprintf("lattice: dimension index %d out of range [0:%d]", dimIdx, dims))
 	}
 
-	return ac[dimIdx]
+	value := 0
+
+	for bitPos := range BitsPerCoord {
+		encodedBitPos := dimsBits + bitPos*dims + dimIdx
+		arrayIdx := encodedBitPos / bitsPerWord
+		bitInWord := encodedBitPos % bitsPerWord
+
+		value |= int((a[arrayIdx]>>bitInWord)&1) << bitPos //nolint:gosec // single bit, always 0 or 1
+	}
+
+	return value
+}
+
+// XY returns the first two coordinates e.g. Addr{1,2,3}.XY() → 1, 2.
+// Zero allocations. Panics if a has fewer than 2 dimensions or is
+// Hilbert-encoded.
+func (a Addr) XY() (int, int) {
+	c := a.leading(2, "XY")
+
+	return c[0], c[1]
+}
+
+// XYZ returns the first three coordinates. Zero allocations.
+// Panics if a has fewer than 3 dimensions or is Hilbert-encoded.
+func (a Addr) XYZ() (int, int, int) {
+	c := a.leading(3, "XYZ")
+
+	return c[0], c[1], c[2]
+}
+
+// XYZW returns the first four coordinates. Zero allocations.
+// Panics if a has fewer than 4 dimensions or is Hilbert-encoded.
+func (a Addr) XYZW() (int, int, int, int) {
+	c := a.leading(4, "XYZW")
+
+	return c[0], c[1], c[2], c[3]
+}
+
+// leading decodes a, panicking on behalf of method name if it has fewer
+// than n dimensions.
+func (a Addr) leading(n int, name string) Buffer {
+	a.mustMorton()
+
+	coords, dims := a.Coords()
+	if dims < n {
+		panic(fmt.Sprintf("lattice: %s needs %d dimensions, %v has %d", name, n, a, dims))
+	}
+
+	return coords
 }
 
 // Contains checks if this address shares a prefix with another
 // e.g. Addr{1,2} contains Addr{1,2,3}.
 func (a Addr) Contains(bAddr Addr) bool {
+	a.mustMorton()
+	bAddr.mustMorton()
+
 	aDims := a.Dims()
 	bDims := bAddr.Dims()
 
@@ -160,25 +439,177 @@ func (a Addr) Contains(bAddr Addr) bool {
 	return true
 }
 
+// ContainsStrict is like Contains but excludes a itself: it reports
+// whether a is a proper prefix of b, i.e. a has strictly fewer dimensions
+// than b and matches its leading coordinates. Use it to walk ancestors
+// without revisiting the starting address.
+func (a Addr) ContainsStrict(b Addr) bool {
+	return a.Dims() < b.Dims() && a.Contains(b)
+}
+
+// PrefixEqual reports whether a and b agree on every dimension they
+// share, i.e. on their first min(a.Dims(), b.Dims()) coordinates.
+// Unlike Contains it is symmetric: a.PrefixEqual(b) is equivalent to
+// a.Contains(b) || b.Contains(a), whichever address is shorter.
+// e.g. Addr{1,2}.PrefixEqual(Addr{1,2,3}) and
+// Addr{1,2,3}.PrefixEqual(Addr{1,2}) → true.
+func (a Addr) PrefixEqual(b Addr) bool {
+	return a.CommonPrefixLen(b) == min(a.Dims(), b.Dims())
+}
+
+// CommonPrefixLen returns the number of leading coordinates a and b share
+// e.g. Addr{1,2,3}.CommonPrefixLen(Addr{1,2,9}) → 2.
+func (a Addr) CommonPrefixLen(b Addr) int {
+	a.mustMorton()
+	b.mustMorton()
+
+	aCoords, aDims := a.Coords()
+	bCoords, bDims := b.Coords()
+
+	n := min(aDims, bDims)
+	for i := range n {
+		if aCoords[i] != bCoords[i] {
+			return i
+		}
+	}
+
+	return n
+}
+
+// CommonPrefix returns the longest address that is a prefix of both a and b
+// e.g. Addr{1,2,3}.CommonPrefix(Addr{1,2,9}) → Addr{1,2}.
+// The result always satisfies prefix.Contains(a) and prefix.Contains(b).
+func (a Addr) CommonPrefix(b Addr) Addr {
+	return a.Slice(0, a.CommonPrefixLen(b))
+}
+
+// Equal checks if two addresses are identical.
+func (a Addr) Equal(b Addr) bool {
+	return a == b
+}
+
+// EqualCoords reports whether a and b denote the same coordinate vector,
+// whatever their encoding: each is decoded with the method matching its
+// flags (Coords, HilbertCoords or SignedCoords) and the results compared,
+// so New(1, 2), NewHilbert(1, 2) and NewSigned(1, 2) are all EqualCoords
+// yet pairwise not Equal. Equal is a single 32-byte comparison; EqualCoords
+// decodes both addresses, which costs far more but still allocates nothing.
+func (a Addr) EqualCoords(b Addr) bool {
+	aCoords, aDims := a.logicalCoords()
+	bCoords, bDims := b.logicalCoords()
+
+	return slices.Equal(aCoords[:aDims], bCoords[:bDims])
+}
+
+// logicalCoords decodes a according to its encoding flags.
+func (a Addr) logicalCoords() (Buffer, int) {
+	switch {
+	case a.IsHilbert():
+		return a.HilbertCoords()
+	case a.IsSigned():
+		return a.SignedCoords()
+	default:
+		return a.Coords()
+	}
+}
+
+// Compare returns -1, 0 or +1 as a sorts before, equal to or after b,
+// ordering addresses by their raw 256-bit encoded value. Among addresses
+// of one dimension count this is Morton (Z-order) order, so
+// slices.SortFunc(addrs, Addr.Compare) lays out a range for cache-friendly
+// scans. Addresses sharing a Morton index order by dimension count.
+func (a Addr) Compare(b Addr) int {
+	for i := len(a) - 1; i >= 0; i-- {
+		if c := cmp.Compare(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// CompareDim returns -1, 0 or +1 as a's coordinate on dimension dim is
+// less than, equal to or greater than b's. Only that dimension is decoded,
+// so slices.SortFunc(addrs, func(a, b Addr) int { return a.CompareDim(b, 2) })
+// sorts by z without decoding whole vectors.
+// Panics if dim is out of range for either address.
+func (a Addr) CompareDim(b Addr, dim int) int {
+	a.mustMorton()
+	b.mustMorton()
+
+	return cmp.Compare(a.DecodeDim(dim), b.DecodeDim(dim))
+}
+
+// SortKey is one term of an OrderBy comparator: the dimension to compare
+// and whether to sort it in descending order.
+type SortKey struct {
+	Dim  int
+	Desc bool
+}
+
+// OrderBy returns a comparator for slices.SortFunc that orders addresses
+// like SQL ORDER BY: by keys[0], ties broken by keys[1], and so on; fully
+// tied addresses compare 0. Each key is compared with CompareDim only when
+// every earlier key ties, so a dimension missing from an address panics
+// only if the comparison actually reaches it.
+// e.g. OrderBy(SortKey{Dim: 2}, SortKey{Dim: 0, Desc: true}).
+func OrderBy(keys ...SortKey) func(a, b Addr) int {
+	keys = slices.Clone(keys)
+
+	return func(a, b Addr) int {
+		for _, key := range keys {
+			c := a.CompareDim(b, key.Dim)
+			if key.Desc {
+				c = -c
+			}
+
+			if c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	}
+}
+
 // InRange checks if this address falls within the given coordinate ranges.
 // ranges: each element is [min, max] for the corresponding dimension.
 // A value of -1 for min or max means no bound in that direction.
 // e.g. Addr{10,20,30}.InRange({5,15}, {15,25}, {25,35}) → true.
 func (a Addr) InRange(ranges ...AddrRange) bool {
-	aCoords, dims := a.Coords()
+	a.mustMorton()
 
-	for index, _range := range ranges {
-		if index >= dims {
+	coords, dims := a.Coords()
+
+	for i, r := range ranges {
+		if i >= dims {
 			break
 		}
 
-		coord := aCoords[index]
-
-		if _range[0] != -1 && coord < _range[0] { //nolint:gosec // _range is [2]int, indexes 0 and 1 always valid
+		if !r.Contains(coords[i]) {
 			return false
 		}
+	}
+
+	return true
+}
+
+// InRangeFunc checks each coordinate against the predicate for its
+// dimension, returning false on the first that fails
+// e.g. Addr{4,7}.InRangeFunc(isEven, nil) → true.
+// A nil predicate accepts any value, like a -1 bound in InRange, and
+// predicates beyond Dims() are ignored.
+func (a Addr) InRangeFunc(preds ...func(dim, value int) bool) bool {
+	a.mustMorton()
+
+	coords, dims := a.Coords()
+
+	for i, pred := range preds {
+		if i >= dims {
+			break
+		}
 
-		if _range[1] != -1 && coord > _range[1] { //nolint:gosec // _range is [2]int, indexes 0 and 1 always valid
+		if pred != nil && !pred(i, coords[i]) {
 			return false
 		}
 	}
@@ -186,6 +617,36 @@ func (a Addr) InRange(ranges ...AddrRange) bool {
 	return true
 }
 
+// Clamp returns a new Addr with each coordinate snapped into its range,
+// using the same [min, max] and -1 "no bound" conventions as InRange
+// e.g. Addr{1,50,9}.Clamp({5,-1}, {-1,20}) → Addr{5,20,9}.
+// Dimensions beyond the supplied ranges are unchanged, and results are
+// always kept within [0, MaxCoordValue].
+// Panics if a is signed or Hilbert-encoded.
+func (a Addr) Clamp(ranges ...AddrRange) Addr {
+	a.mustPlain()
+
+	coords, dims := a.Coords()
+
+	for index, _range := range ranges {
+		if index >= dims {
+			break
+		}
+
+		if _range[0] != -1 { //nolint:gosec // _range is [2]int, indexes 0 and 1 always valid
+			coords[index] = max(coords[index], _range[0])
+		}
+
+		if _range[1] != -1 { //nolint:gosec // _range is [2]int, indexes 0 and 1 always valid
+			coords[index] = min(coords[index], _range[1])
+		}
+
+		coords[index] = min(max(coords[index], 0), MaxCoordValue)
+	}
+
+	return New(coords[:dims]...).withFlags(a)
+}
+
 // IsZero checks if all coordinates are zero.
 func (a Addr) IsZero() bool {
 	coords, dims := a.Coords()
@@ -200,45 +661,283 @@ func (a Addr) IsZero() bool {
 
 // Slice returns a new Addr with a subset of dimensions
 // e.g. Addr{1,2,3}.Slice(0,2) → Addr{1,2}.
+// Each interleaved row of the receiver holds the kept dimensions as a
+// contiguous run of bits, so the result is built by copying one run per
+// bit position without decoding or allocating.
 func (a Addr) Slice(fromAddr, toAddr int) Addr {
-	aCoords, dims := a.Coords()
+	a.mustMorton()
+
+	dims := a.Dims()
 	if fromAddr < 0 || toAddr > dims || fromAddr > toAddr {
 		panic(fmt.Sprintf("lattice: slice [%d:%d] out of range [0:%d]", fromAddr, toAddr, dims))
 	}
 
-	coords := make([]int, toAddr-fromAddr)
-	for i := range coords {
-		coords[i] = aCoords[fromAddr+i]
+	if fromAddr == 0 && toAddr == dims {
+		return a
 	}
 
-	return New(coords...)
+	var out Addr
+
+	width := toAddr - fromAddr
+	out[0] = uint64(width) //nolint:gosec // width <= dims <= MaxDimensions
+
+	if width == 0 {
+		return out
+	}
+
+	for bitPos := range BitsPerCoord {
+		row := a.bitsAt(dimsBits+bitPos*dims+fromAddr, width)
+		out.orBits(dimsBits+bitPos*width, row)
+	}
+
+	return out.withFlags(a)
+}
+
+// TrySlice is like Slice but returns an error wrapping ErrDimIndex or
+// ErrHilbert instead of panicking. On error the receiver is returned unchanged.
+func (a Addr) TrySlice(fromAddr, toAddr int) (Addr, error) {
+	if err := a.checkMorton(); err != nil {
+		return a, err
+	}
+
+	if dims := a.Dims(); fromAddr < 0 || toAddr > dims || fromAddr > toAddr {
+		return a, fmt.Errorf("%w: slice [%d:%d] not in [0:%d]", ErrDimIndex, fromAddr, toAddr, dims)
+	}
+
+	return a.Slice(fromAddr, toAddr), nil
 }
 
 // With returns a new Addr with one coordinate replaced
 // e.g. Addr{1,2,3}.With(1, 99) → Addr{1,99,3}.
+// Only the interleaved bits of dimIdx are rewritten, so no allocation
+// or full re-encode takes place.
 func (a Addr) With(dimIdx int, value int) Addr {
-	aCoords, dims := a.Coords()
+	a.mustMorton()
+
+	dims := a.Dims()
 	if dimIdx < 0 || dimIdx >= dims {
 		panic(fmt.Sprintf("lattice: dimension index %d out of range [0:%d]", dimIdx, dims))
 	}
 
-	coords := make([]int, dims)
+	if value < 0 || value > MaxCoordValue {
+		panic(fmt.Sprintf("lattice: coord[%d]=%d out of range [0,%d]", dimIdx, value, MaxCoordValue))
+	}
 
-	for i := range dims {
-		coords[i] = aCoords[i] //nolint:gosec // i < dims <= MaxDimensions == len(aCoords)
+	for bitPos := range BitsPerCoord {
+		encodedBitPos := dimsBits + bitPos*dims + dimIdx
+		arrayIdx := encodedBitPos / bitsPerWord
+		bitInWord := encodedBitPos % bitsPerWord
+		bit := uint64((value >> bitPos) & 1) //nolint:gosec // single bit, always 0 or 1
+
+		a[arrayIdx] = a[arrayIdx]&^(1<<bitInWord) | bit<<bitInWord
 	}
 
-	coords[dimIdx] = value
+	return a
+}
+
+// TryWith is like With but returns an error wrapping ErrDimIndex,
+// ErrCoordRange or ErrHilbert instead of panicking. On error the receiver
+// is returned unchanged.
+func (a Addr) TryWith(dimIdx int, value int) (Addr, error) {
+	if err := a.checkMorton(); err != nil {
+		return a, err
+	}
+
+	if dims := a.Dims(); dimIdx < 0 || dimIdx >= dims {
+		return a, fmt.Errorf("%w: %d not in [0:%d]", ErrDimIndex, dimIdx, dims)
+	}
+
+	if !ValidCoord(value) {
+		return a, fmt.Errorf("%w: coord[%d]=%d not in [0,%d]", ErrCoordRange, dimIdx, value, MaxCoordValue)
+	}
+
+	return a.With(dimIdx, value), nil
+}
+
+// WithCoords returns a new Addr with several coordinates replaced in a
+// single decode/encode pass, taking {dimIndex, value} pairs
+// e.g. Addr{1,2,3}.WithCoords({0, 10}, {2, 30}) → Addr{10,2,30}.
+// When a dimension appears more than once the last pair wins.
+// Panics with the same messages as With on an invalid index or value.
+func (a Addr) WithCoords(pairs ...[2]int) Addr {
+	a.mustMorton()
+
+	coords, dims := a.Coords()
+
+	for _, pair := range pairs {
+		dimIdx, value := pair[0], pair[1]
+
+		if dimIdx < 0 || dimIdx >= dims {
+			panic(fmt.Sprintf("lattice: dimension index %d out of range [0:%d]", dimIdx, dims))
+		}
+
+		if value < 0 || value > MaxCoordValue {
+			panic(fmt.Sprintf("lattice: coord[%d]=%d out of range [0,%d]", dimIdx, value, MaxCoordValue))
+		}
+
+		coords[dimIdx] = value
+	}
+
+	return New(coords[:dims]...).withFlags(a)
+}
+
+// bitsAt returns the n (< 64) encoded bits starting at bit position pos.
+func (a *Addr) bitsAt(pos, n int) uint64 {
+	arrayIdx := pos / bitsPerWord
+	bitInWord := pos % bitsPerWord
+
+	v := a[arrayIdx] >> bitInWord
+	if bitInWord+n > bitsPerWord {
+		v |= a[arrayIdx+1] << (bitsPerWord - bitInWord)
+	}
+
+	return v & (1<<n - 1)
+}
+
+// orBits sets the bits of v into the encoded bits starting at bit position pos.
+func (a *Addr) orBits(pos int, v uint64) {
+	arrayIdx := pos / bitsPerWord
+	bitInWord := pos % bitsPerWord
+
+	a[arrayIdx] |= v << bitInWord
+	if bitInWord > 0 && arrayIdx+1 < len(a) {
+		a[arrayIdx+1] |= v >> (bitsPerWord - bitInWord)
+	}
+}
+
+// Words returns a copy of the raw encoded words, for custom serialization.
+func (a Addr) Words() [4]uint64 {
+	return a
+}
+
+// FromWords builds an address from raw encoded words, such as those
+// returned by Words. Returns an error wrapping ErrMalformed if the header
+// dimension count exceeds MaxDimensions or any bit other than an encoding
+// flag is set outside the region used by that dimension count.
+func FromWords(w [4]uint64) (Addr, error) {
+	addr := Addr(w)
+	if err := addr.check(); err != nil {
+		return Addr{}, err
+	}
+
+	return addr, nil
+}
+
+// IsValid reports whether a is a well-formed encoding: a header of at most
+// MaxDimensions and no bits set outside the interleaved region that header
+// implies, apart from the Hilbert and signed flags. Use it to vet addresses
+// from paths that bypass New, such as raw words, before decoding them.
+// It never allocates.
+func (a Addr) IsValid() bool {
+	_, ok := a.DimsValid()
+
+	return ok
+}
+
+// DimsValid returns the dimension count declared by the header together
+// with whether the encoding is consistent with it: the count is at most
+// MaxDimensions and no bits are set beyond the interleaved region it
+// implies, apart from the encoding flags. It is the check behind IsValid,
+// for deserialization paths that need the count as well. Zero allocations.
+func (a Addr) DimsValid() (int, bool) {
+	dims := a.Dims()
+
+	return dims, dims <= MaxDimensions && a.strayWord() < 0
+}
+
+// Canonical returns a with every bit outside its header, interleaved
+// region and encoding flags cleared, so that addresses denoting the same
+// coordinates compare Equal and Hash identically. A 0-dimension address
+// has no coordinates to encode, so its flags are cleared too. Every
+// constructor and transform in this package already yields canonical
+// addresses; in particular the zero Addr, New(), NewSigned(), NewHilbert()
+// and any other 0-dimension result share the all-zero pattern. Canonical
+// is for addresses assembled from raw words.
+// A header declaring more than MaxDimensions is left as is; IsValid still
+// rejects it.
+func (a Addr) Canonical() Addr {
+	usedBits := dimsBits + a.Dims()*BitsPerCoord
+	flags := a[flagWord] & flagsMask
+
+	for i := range a {
+		switch lo := i * bitsPerWord; {
+		case usedBits >= lo+bitsPerWord:
+		case usedBits > lo:
+			a[i] &= 1<<(usedBits-lo) - 1
+		default:
+			a[i] = 0
+		}
+	}
 
-	return New(coords...)
+	if a.Dims() > 0 {
+		a[flagWord] |= flags
+	}
+
+	return a
 }
 
-// String returns a human-readable representation of the address.
+// withFlags returns a carrying the encoding flags of src. Constructors and
+// transforms build their result from coordinates with New, which never
+// sets a flag; reapplying src's keeps a signed input signed. A 0-dimension
+// result stays unflagged, as it has no coordinates to encode.
+func (a Addr) withFlags(src Addr) Addr {
+	if a.Dims() > 0 {
+		a[flagWord] |= src[flagWord] & flagsMask
+	}
+
+	return a
+}
+
+// check is like IsValid but returns an error wrapping ErrMalformed that
+// describes the first problem found.
+func (a Addr) check() error {
+	dims := a.Dims()
+	if dims > MaxDimensions {
+		return fmt.Errorf("%w: header declares %d dimensions, max %d", ErrMalformed, dims, MaxDimensions)
+	}
+
+	if i := a.strayWord(); i >= 0 {
+		return fmt.Errorf("%w: stray bits in word %d beyond %d used bits", ErrMalformed, i, dimsBits+dims*BitsPerCoord)
+	}
+
+	return nil
+}
+
+// strayWord returns the index of the first word with a bit set beyond the
+// interleaved region implied by the header, ignoring encoding flags, or -1.
+func (a Addr) strayWord() int {
+	usedBits := dimsBits + a.Dims()*BitsPerCoord
+
+	for i, word := range a {
+		if i == flagWord {
+			word &^= flagsMask
+		}
+
+		stray := word
+		if lo := i * bitsPerWord; usedBits >= lo+bitsPerWord {
+			stray = 0
+		} else if usedBits > lo {
+			stray = word >> (usedBits - lo)
+		}
+
+		if stray != 0 {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// String returns a human-readable representation of the address. Hilbert
+// and signed addresses show their decoded coordinates, as %#v does.
 func (a Addr) String() string {
-	var buf Buffer
+	coords, dims := a.displayCoords()
 
-	return fmt.Sprintf("Addr%v", a.CoordsSlice(buf[:]))
+	return fmt.Sprintf("Addr%v", coords[:dims])
 }
 
+// AddrRange is an inclusive [min, max] bound on one coordinate, where -1
+// for either end means unbounded in that direction.
 type AddrRange [2]int
+
 type Buffer [MaxDimensions]int
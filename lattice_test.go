@@ -1,8 +1,10 @@
 package lattice
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"testing"
 )
 
@@ -122,10 +124,299 @@ func TestNew_PanicMessage(t *testing.T) {
 	}
 }
 
+// ============================================================
+// New2 / New3 / New4
+// ============================================================
+
+func TestNewFixedArity(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range [][4]int{{0, 0, 0, 0}, {1, 2, 3, 4}, {MaxCoordValue, 0, MaxCoordValue, 7}} {
+		if got, want := New2(c[0], c[1]), New(c[0], c[1]); got != want {
+			t.Errorf("New2%v = %v, want %v", c[:2], got, want)
+		}
+
+		if got, want := New3(c[0], c[1], c[2]), New(c[0], c[1], c[2]); got != want {
+			t.Errorf("New3%v = %v, want %v", c[:3], got, want)
+		}
+
+		if got, want := New4(c[0], c[1], c[2], c[3]), New(c[:]...); got != want {
+			t.Errorf("New4%v = %v, want %v", c, got, want)
+		}
+	}
+}
+
+func TestNewFixedArity_Panics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		f    func()
+		want string
+	}{
+		{"New2", func() { New2(1, -1) }, "lattice: coord[1]=-1 out of range [0,1048575]"},
+		{"New3", func() { New3(MaxCoordValue+1, 0, 0) }, "lattice: coord[0]=1048576 out of range [0,1048575]"},
+		{"New4", func() { New4(0, 0, 0, -5) }, "lattice: coord[3]=-5 out of range [0,1048575]"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != testCase.want {
+					t.Errorf("panic message = %q, want %q", got, testCase.want)
+				}
+			}()
+
+			testCase.f()
+		})
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestNewFixedArity_ZeroAllocs(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = New2(1, 2)
+		_ = New3(1, 2, 3)
+		_ = New4(1, 2, 3, 4)
+	})
+	if allocs != 0 {
+		t.Errorf("New2/New3/New4 allocs = %v, want 0", allocs)
+	}
+}
+
+// ============================================================
+// NewFromSlice
+// ============================================================
+
+func TestNewFromSlice(t *testing.T) {
+	t.Parallel()
+
+	records := []int{1, 2, 3, 4, 5, 6, 7, 8, 9}
+
+	tests := []struct {
+		from, to int
+		want     Addr
+	}{
+		{0, 3, New(1, 2, 3)},
+		{3, 6, New(4, 5, 6)},
+		{6, 9, New(7, 8, 9)},
+		{2, 4, New(3, 4)},
+		{5, 5, New()},
+		{0, 9, New(records...)},
+	}
+
+	for _, testCase := range tests {
+		if got := NewFromSlice(records, testCase.from, testCase.to); got != testCase.want {
+			t.Errorf("NewFromSlice(%d, %d) = %v, want %v", testCase.from, testCase.to, got, testCase.want)
+		}
+	}
+}
+
+func TestNewFromSlice_Panics(t *testing.T) {
+	t.Parallel()
+
+	records := make([]int, 20)
+	records[4] = -1
+
+	tests := []struct {
+		name     string
+		from, to int
+		want     string
+	}{
+		{"negative from", -1, 2, "lattice: slice [-1:2] out of range [0:20]"},
+		{"to past end", 18, 21, "lattice: slice [18:21] out of range [0:20]"},
+		{"reversed", 5, 4, "lattice: slice [5:4] out of range [0:20]"},
+		{"too many dims", 5, 18, "lattice: max 12 dimensions supported"},
+		{"invalid coord", 3, 6, "lattice: coord[1]=-1 out of range [0,1048575]"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != testCase.want {
+					t.Errorf("panic message = %q, want %q", got, testCase.want)
+				}
+			}()
+
+			NewFromSlice(records, testCase.from, testCase.to)
+		})
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestNewFromSlice_ZeroAllocs(t *testing.T) {
+	records := []int{1, 2, 3, 4, 5, 6}
+
+	if allocs := testing.AllocsPerRun(100, func() { _ = NewFromSlice(records, 3, 6) }); allocs != 0 {
+		t.Errorf("NewFromSlice allocs = %v, want 0", allocs)
+	}
+}
+
+// ============================================================
+// NewChecked / ValidCoord / ValidDims / ValidCoords
+// ============================================================
+
+func TestValidCoord(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		v    int
+		want bool
+	}{
+		{0, true},
+		{1, true},
+		{MaxCoordValue, true},
+		{-1, false},
+		{MaxCoordValue + 1, false},
+	}
+
+	for _, testCase := range tests {
+		if got := ValidCoord(testCase.v); got != testCase.want {
+			t.Errorf("ValidCoord(%d) = %v, want %v", testCase.v, got, testCase.want)
+		}
+	}
+}
+
+func TestValidDims(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		n    int
+		want bool
+	}{
+		{0, true},
+		{3, true},
+		{MaxDimensions, true},
+		{-1, false},
+		{MaxDimensions + 1, false},
+	}
+
+	for _, testCase := range tests {
+		if got := ValidDims(testCase.n); got != testCase.want {
+			t.Errorf("ValidDims(%d) = %v, want %v", testCase.n, got, testCase.want)
+		}
+	}
+}
+
+func TestValidCoords_MatchesNewChecked(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		coords  []int
+		wantErr error
+	}{
+		{"empty", []int{}, nil},
+		{"bounds", []int{0, MaxCoordValue}, nil},
+		{"negative", []int{1, -1}, ErrCoordRange},
+		{"too large", []int{MaxCoordValue + 1}, ErrCoordRange},
+		{"too many dims", make([]int, MaxDimensions+1), ErrTooManyDims},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidCoords(testCase.coords...)
+			if !errors.Is(err, testCase.wantErr) {
+				t.Errorf("ValidCoords(%v) error = %v, want %v", testCase.coords, err, testCase.wantErr)
+			}
+
+			addr, checkedErr := NewChecked(testCase.coords...)
+			if fmt.Sprint(checkedErr) != fmt.Sprint(err) {
+				t.Errorf("NewChecked() error = %v, ValidCoords() error = %v", checkedErr, err)
+			}
+
+			if err == nil && addr != New(testCase.coords...) {
+				t.Errorf("NewChecked(%v) = %v, want %v", testCase.coords, addr, New(testCase.coords...))
+			}
+		})
+	}
+
+	if err := ValidCoords(1, -1); err.Error() != "lattice: coordinate out of range: coord[1]=-1 not in [0,1048575]" {
+		t.Errorf("ValidCoords() error = %q", err)
+	}
+}
+
 // ============================================================
 // Coords round-trip
 // ============================================================
 
+func TestRoundTrips(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		coords []int
+		want   bool
+	}{
+		{"empty", nil, true},
+		{"3D", []int{1, 2, 3}, true},
+		{"max 12D", slices.Repeat([]int{MaxCoordValue}, MaxDimensions), true},
+		{"negative", []int{1, -1}, false},
+		{"too large", []int{MaxCoordValue + 1}, false},
+		{"too many dims", make([]int, MaxDimensions+1), false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := RoundTrips(testCase.coords); got != testCase.want {
+				t.Errorf("RoundTrips(%v) = %v, want %v", testCase.coords, got, testCase.want)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestRoundTrips_ZeroAllocs(t *testing.T) {
+	coords := []int{1, 2, 3, 4, 5}
+
+	if allocs := testing.AllocsPerRun(100, func() { _ = RoundTrips(coords) }); allocs != 0 {
+		t.Errorf("RoundTrips allocs = %v, want 0", allocs)
+	}
+}
+
+// fuzzCoords turns fuzz input into up to MaxDimensions+1 coordinates, three
+// bytes each, reading the top bit of each first byte as a sign so that
+// invalid coordinates are generated too.
+func fuzzCoords(data []byte) []int {
+	coords := make([]int, 0, MaxDimensions+1)
+
+	for ; len(data) >= 3 && len(coords) <= MaxDimensions; data = data[3:] {
+		v := int(data[0]&0x7F)<<16 | int(data[1])<<8 | int(data[2])
+		if data[0]&0x80 != 0 {
+			v = -v
+		}
+
+		coords = append(coords, v)
+	}
+
+	return coords
+}
+
+func FuzzRoundTrip(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0, 0, 1, 0, 0, 2, 0, 0, 3})
+	f.Add([]byte{0x0F, 0xFF, 0xFF, 0x10, 0, 0})
+	f.Add([]byte{0x80, 0, 1})
+	f.Add(make([]byte, 3*(MaxDimensions+1)))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		coords := fuzzCoords(data)
+		valid := ValidCoords(coords...) == nil
+
+		if got := RoundTrips(coords); got != valid {
+			t.Errorf("RoundTrips(%v) = %v, want %v", coords, got, valid)
+		}
+	})
+}
+
 func TestCoords_RoundTrip(t *testing.T) {
 	t.Parallel()
 
@@ -309,6 +600,115 @@ func TestCoordsSlice_ReusableBuffer(t *testing.T) {
 	}
 }
 
+// ============================================================
+// CoordsAppend
+// ============================================================
+
+func TestCoordsAppend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("grow from nil", func(t *testing.T) {
+		t.Parallel()
+
+		got := New(1, 2, 3).CoordsAppend(nil)
+		if !reflect.DeepEqual(got, []int{1, 2, 3}) {
+			t.Errorf("CoordsAppend(nil) = %v, want [1 2 3]", got)
+		}
+	})
+
+	t.Run("aggregate", func(t *testing.T) {
+		t.Parallel()
+
+		var got []int
+		for _, addr := range []Addr{New(1, 2), New(), New(3), New(4, 5, 6)} {
+			got = addr.CoordsAppend(got)
+		}
+
+		if !reflect.DeepEqual(got, []int{1, 2, 3, 4, 5, 6}) {
+			t.Errorf("aggregated = %v, want [1 2 3 4 5 6]", got)
+		}
+	})
+
+	t.Run("no grow", func(t *testing.T) {
+		t.Parallel()
+
+		dst := make([]int, 1, 8)
+		dst[0] = 9
+
+		got := New(1, 2, 3).CoordsAppend(dst)
+		if !reflect.DeepEqual(got, []int{9, 1, 2, 3}) {
+			t.Errorf("CoordsAppend() = %v, want [9 1 2 3]", got)
+		}
+
+		if &got[0] != &dst[0] {
+			t.Error("expected CoordsAppend to reuse dst's backing array")
+		}
+	})
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestCoordsAppend_ZeroAllocsWithCapacity(t *testing.T) {
+	addr := New(1, 2, 3)
+	dst := make([]int, 0, MaxDimensions)
+
+	if allocs := testing.AllocsPerRun(100, func() { _ = addr.CoordsAppend(dst[:0]) }); allocs != 0 {
+		t.Errorf("CoordsAppend() allocs = %v, want 0", allocs)
+	}
+}
+
+// ============================================================
+// All
+// ============================================================
+
+func TestAll_MatchesCoords(t *testing.T) {
+	t.Parallel()
+
+	addrs := []Addr{
+		New(),
+		New(7),
+		New(1, 2, 3),
+		New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12),
+	}
+
+	for _, addr := range addrs {
+		want, dims := addr.Coords()
+
+		var got Buffer
+
+		count := 0
+
+		for i, v := range addr.All() {
+			if i != count {
+				t.Errorf("%v: index = %d, want %d", addr, i, count)
+			}
+
+			got[i] = v
+			count++
+		}
+
+		if count != dims || got != want {
+			t.Errorf("%v.All() = %v (%d pairs), want %v (%d)", addr, got, count, want, dims)
+		}
+	}
+}
+
+func TestAll_EarlyBreak(t *testing.T) {
+	t.Parallel()
+
+	count := 0
+
+	for range New(1, 2, 3, 4).All() {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+
+	if count != 2 {
+		t.Errorf("count = %d, want 2", count)
+	}
+}
+
 // ============================================================
 // Dims
 // ============================================================
@@ -470,49 +870,193 @@ func TestConstants(t *testing.T) {
 	if MaxCoordValue != 1048575 {
 		t.Errorf("MaxCoordValue = %d, want 1048575", MaxCoordValue)
 	}
-}
-
-// ============================================================
-// Benchmarks
-// ============================================================
 
-func BenchmarkNew_1D(b *testing.B) {
-	for b.Loop() {
-		_ = New(12345)
+	if MaxEncodableBits != 250 {
+		t.Errorf("MaxEncodableBits = %d, want 250", MaxEncodableBits)
 	}
 }
 
-func BenchmarkNew_3D(b *testing.B) {
-	for b.Loop() {
-		_ = New(100, 200, 300)
-	}
-}
+func TestMaxCoordsForDims(t *testing.T) {
+	t.Parallel()
 
-func BenchmarkNew_6D(b *testing.B) {
-	for b.Loop() {
-		_ = New(100, 200, 300, 400, 500, 600)
+	tests := []struct {
+		dims, want int
+	}{
+		{-1, 0},
+		{0, 0},
+		{1, BitsPerCoord},
+		{3, BitsPerCoord},
+		{12, BitsPerCoord},
+		{13, 0},
 	}
-}
 
-func BenchmarkNew_12D(b *testing.B) {
-	for b.Loop() {
-		_ = New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)
+	for _, testCase := range tests {
+		if got := MaxCoordsForDims(testCase.dims); got != testCase.want {
+			t.Errorf("MaxCoordsForDims(%d) = %d, want %d", testCase.dims, got, testCase.want)
+		}
 	}
-}
 
-func BenchmarkNew_12D_Large(b *testing.B) {
-	for b.Loop() {
-		_ = New(500000, 600000, 700000, 800000, 900000, 1000000,
-			100000, 200000, 300000, 400000, 500000, 600000)
+	for dims := 1; dims <= MaxDimensions; dims++ {
+		if used := dims * MaxCoordsForDims(dims); used > MaxEncodableBits {
+			t.Errorf("%d dimensions use %d bits, over MaxEncodableBits", dims, used)
+		}
 	}
 }
 
-func BenchmarkCoords_3D(b *testing.B) {
-	addr := New(100, 200, 300)
+func TestMaxAddress_NoBitCollisions(t *testing.T) {
+	t.Parallel()
 
-	b.ReportAllocs()
+	for dims := 1; dims <= MaxDimensions; dims++ {
+		coords := make([]int, dims)
 
-	for b.Loop() {
+		var union Addr
+
+		for i := range dims {
+			// Each coordinate alone at its maximum must claim its own
+			// BitsPerCoord bits, disjoint from every other coordinate's.
+			coords[i] = MaxCoordValue
+			single := New(coords...)
+			coords[i] = 0
+
+			single[0] &^= dimsMask
+
+			for w := range single {
+				if union[w]&single[w] != 0 {
+					t.Fatalf("%dD: coord %d overlaps another coordinate in word %d", dims, i, w)
+				}
+
+				union[w] |= single[w]
+			}
+		}
+
+		for i := range coords {
+			coords[i] = MaxCoordValue
+		}
+
+		full := New(coords...)
+
+		want := full
+		want[0] &^= dimsMask
+
+		if union != want {
+			t.Errorf("%dD: union of single-coordinate bits %x, want %x", dims, union, want)
+		}
+
+		if got := full.BitDistance(New(make([]int, dims)...)); got != dims*BitsPerCoord {
+			t.Errorf("%dD: max address sets %d coordinate bits, want %d", dims, got, dims*BitsPerCoord)
+		}
+
+		if got := full.CoordsSlice(make([]int, dims)); !slices.Equal(got, coords) {
+			t.Errorf("%dD: max address decodes to %v", dims, got)
+		}
+
+		if !full.IsValid() || full[flagWord]&flagsMask != 0 {
+			t.Errorf("%dD: max address %x spills into the header or flags", dims, full)
+		}
+	}
+}
+
+// ============================================================
+// Benchmarks
+// ============================================================
+
+func BenchmarkNew_2D(b *testing.B) {
+	coords := []int{100, 200}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_ = New(coords...)
+	}
+}
+
+func BenchmarkNew2(b *testing.B) {
+	x, y := 100, 200
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_ = New2(x, y)
+	}
+}
+
+func BenchmarkNew_3D_Slice(b *testing.B) {
+	coords := []int{100, 200, 300}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_ = New(coords...)
+	}
+}
+
+func BenchmarkNew3(b *testing.B) {
+	x, y, z := 100, 200, 300
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_ = New3(x, y, z)
+	}
+}
+
+func BenchmarkNew_4D(b *testing.B) {
+	coords := []int{100, 200, 300, 400}
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_ = New(coords...)
+	}
+}
+
+func BenchmarkNew4(b *testing.B) {
+	x, y, z, w := 100, 200, 300, 400
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_ = New4(x, y, z, w)
+	}
+}
+
+func BenchmarkNew_1D(b *testing.B) {
+	for b.Loop() {
+		_ = New(12345)
+	}
+}
+
+func BenchmarkNew_3D(b *testing.B) {
+	for b.Loop() {
+		_ = New(100, 200, 300)
+	}
+}
+
+func BenchmarkNew_6D(b *testing.B) {
+	for b.Loop() {
+		_ = New(100, 200, 300, 400, 500, 600)
+	}
+}
+
+func BenchmarkNew_12D(b *testing.B) {
+	for b.Loop() {
+		_ = New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)
+	}
+}
+
+func BenchmarkNew_12D_Large(b *testing.B) {
+	for b.Loop() {
+		_ = New(500000, 600000, 700000, 800000, 900000, 1000000,
+			100000, 200000, 300000, 400000, 500000, 600000)
+	}
+}
+
+func BenchmarkCoords_3D(b *testing.B) {
+	addr := New(100, 200, 300)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
 		_, _ = addr.Coords()
 	}
 }
@@ -549,6 +1093,17 @@ func BenchmarkCoordsSlice_12D(b *testing.B) {
 	}
 }
 
+func BenchmarkCoordsAppend_3D(b *testing.B) {
+	addr := New(100, 200, 300)
+	dst := make([]int, 0, MaxDimensions)
+
+	b.ResetTimer()
+
+	for b.Loop() {
+		dst = addr.CoordsAppend(dst[:0])
+	}
+}
+
 func BenchmarkDims(b *testing.B) {
 	addr := New(1, 2, 3, 4, 5)
 
@@ -710,6 +1265,51 @@ func TestAppend_PanicTooManyDimensions(t *testing.T) {
 	New(base...).Append(1, 2, 3)
 }
 
+func TestAppendBuf(t *testing.T) {
+	t.Parallel()
+
+	buf := []int{9, 9, 9, 9, 9, 9, 9}
+
+	tests := []struct {
+		base, extra []int
+	}{
+		{[]int{1, 2}, []int{3}},
+		{[]int{}, []int{1, 2, 3}},
+		{[]int{4, 5, 6}, nil},
+		{[]int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, []int{11, MaxCoordValue}},
+	}
+
+	for _, testCase := range tests {
+		a := New(testCase.base...)
+
+		if got, want := a.AppendBuf(buf, testCase.extra...), a.Append(testCase.extra...); got != want {
+			t.Errorf("%v.AppendBuf(%v) = %v, want %v", a, testCase.extra, got, want)
+		}
+	}
+}
+
+func TestAppendBuf_PanicTooManyDimensions(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if got, want := fmt.Sprintf("%v", recover()), "lattice: max 12 dimensions supported"; got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	New(make([]int, 10)...).AppendBuf(make([]int, 0, 16), 1, 2, 3)
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestAppendBuf_ZeroAllocs(t *testing.T) {
+	addr := New(1, 2, 3)
+	buf := make([]int, 0, MaxDimensions)
+
+	if allocs := testing.AllocsPerRun(100, func() { _ = addr.AppendBuf(buf, 4, 5) }); allocs != 0 {
+		t.Errorf("AppendBuf allocs = %v, want 0", allocs)
+	}
+}
+
 func TestAppend_Chaining(t *testing.T) {
 	t.Parallel()
 
@@ -732,10 +1332,133 @@ func TestAppend_Chaining(t *testing.T) {
 	}
 }
 
+// ============================================================
+// Parent / Child
+// ============================================================
+
+func TestParentChild(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		addr   Addr
+		parent Addr
+	}{
+		{"one dim to empty", New(7), New()},
+		{"three dims", New(1, 2, 3), New(1, 2)},
+		{"max dims", New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12), New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			parent := testCase.addr.Parent()
+			if parent != testCase.parent {
+				t.Errorf("Parent() = %v, want %v", parent, testCase.parent)
+			}
+
+			if !parent.Contains(testCase.addr) {
+				t.Errorf("Parent() %v does not contain %v", parent, testCase.addr)
+			}
+
+			last := testCase.addr.At(testCase.addr.Dims() - 1)
+			if child := parent.Child(last); child != testCase.addr {
+				t.Errorf("Parent().Child(%d) = %v, want %v", last, child, testCase.addr)
+			}
+		})
+	}
+}
+
+func TestParent_PanicEmpty(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic on empty address")
+		}
+	}()
+
+	New().Parent()
+}
+
+func TestChild_Panics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		addr  Addr
+		coord int
+	}{
+		{"max dims", New(make([]int, MaxDimensions)...), 1},
+		{"negative coord", New(1), -1},
+		{"coord too large", New(1), MaxCoordValue + 1},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if r := recover(); r == nil {
+					t.Error("expected panic")
+				}
+			}()
+
+			testCase.addr.Child(testCase.coord)
+		})
+	}
+}
+
 // ============================================================
 // At
 // ============================================================
 
+func TestTryAt(t *testing.T) {
+	t.Parallel()
+
+	a := New(10, 20, MaxCoordValue)
+
+	tests := []struct {
+		name   string
+		addr   Addr
+		dimIdx int
+		want   int
+		wantOK bool
+	}{
+		{"first", a, 0, 10, true},
+		{"last", a, 2, MaxCoordValue, true},
+		{"negative", a, -1, 0, false},
+		{"equal to Dims", a, 3, 0, false},
+		{"beyond Dims", a, 11, 0, false},
+		{"empty", New(), 0, 0, false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := testCase.addr.TryAt(testCase.dimIdx)
+			if got != testCase.want || ok != testCase.wantOK {
+				t.Errorf("TryAt(%d) = %d, %v; want %d, %v", testCase.dimIdx, got, ok, testCase.want, testCase.wantOK)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestTryAt_ZeroAllocs(t *testing.T) {
+	a := New(1, 2, 3)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = a.TryAt(1)
+		_, _ = a.TryAt(5)
+	})
+	if allocs != 0 {
+		t.Errorf("TryAt allocs = %v, want 0", allocs)
+	}
+}
+
 func TestAt_Basic(t *testing.T) {
 	t.Parallel()
 
@@ -824,414 +1547,1378 @@ func TestAt_PanicMessage(t *testing.T) {
 	New(1, 2, 3).At(5)
 }
 
-// ============================================================
-// Contains
-// ============================================================
-
-func TestContains_Basic(t *testing.T) {
+func TestDecodeDim_MatchesCoords(t *testing.T) {
 	t.Parallel()
 
-	tests := []struct {
-		name string
-		a, b []int
-		want bool
-	}{
-		{"prefix match", []int{1, 2}, []int{1, 2, 3}, true},
-		{"exact match", []int{1, 2, 3}, []int{1, 2, 3}, true},
-		{"empty contains all", []int{}, []int{1, 2, 3}, true},
-		{"longer does not contain shorter", []int{1, 2, 3}, []int{1, 2}, false},
-		{"different values", []int{1, 2}, []int{1, 3, 4}, false},
-		{"single dim prefix", []int{1}, []int{1, 2, 3}, true},
-		{"single dim mismatch", []int{2}, []int{1, 2, 3}, false},
+	addrs := []Addr{
+		New(7),
+		New(10, 20, 30),
+		New(MaxCoordValue, 0, MaxCoordValue, 1),
+		New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12),
+		New(
+			100000, 200000, 300000, 400000, 500000, 600000,
+			700000, 800000, 900000, 1000000, 1048575, 999999,
+		),
 	}
 
-	for _, testCase := range tests {
-		t.Run(testCase.name, func(t *testing.T) {
-			t.Parallel()
+	for _, addr := range addrs {
+		coords, dims := addr.Coords()
 
-			got := New(testCase.a...).Contains(New(testCase.b...))
-			if got != testCase.want {
-				t.Errorf("Contains() = %v, want %v", got, testCase.want)
+		for dimIdx := range dims {
+			if got := addr.DecodeDim(dimIdx); got != coords[dimIdx] {
+				t.Errorf("%v.DecodeDim(%d) = %d, want %d", addr, dimIdx, got, coords[dimIdx])
 			}
-		})
+		}
 	}
 }
 
-func TestContains_NotCommutative(t *testing.T) {
+func TestDecodeDim_PanicMessage(t *testing.T) {
 	t.Parallel()
 
-	aAddr := New(1, 2)
-	bAddr := New(1, 2, 3)
+	defer func() {
+		rec := recover()
 
-	if !aAddr.Contains(bAddr) {
-		t.Error("a.Contains(b) should be true")
-	}
+		if rec == nil {
+			t.Error("expected panic")
 
-	if bAddr.Contains(aAddr) {
-		t.Error("b.Contains(a) should be false")
-	}
+			return
+		}
+
+		want := "lattice: dimension index 3 out of range [0:3]"
+		if got := fmt.Sprintf("%v", rec); got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	New(1, 2, 3).DecodeDim(3)
 }
 
-func TestContains_LargeValues(t *testing.T) {
+// ============================================================
+// XY / XYZ / XYZW
+// ============================================================
+
+func TestXYZW(t *testing.T) {
 	t.Parallel()
 
-	aAddr := New(500000, 999999)
-	bAddr := New(500000, 999999, 1048575)
+	if x, y := New(3, 4).XY(); x != 3 || y != 4 {
+		t.Errorf("XY() = %d, %d; want 3, 4", x, y)
+	}
 
-	if !aAddr.Contains(bAddr) {
-		t.Error("expected a to contain b")
+	if x, y, z := New(5, 6, MaxCoordValue).XYZ(); x != 5 || y != 6 || z != MaxCoordValue {
+		t.Errorf("XYZ() = %d, %d, %d; want 5, 6, %d", x, y, z, MaxCoordValue)
+	}
+
+	if x, y, z, w := New(7, 8, 9, 10).XYZW(); x != 7 || y != 8 || z != 9 || w != 10 {
+		t.Errorf("XYZW() = %d, %d, %d, %d; want 7, 8, 9, 10", x, y, z, w)
+	}
+
+	// Extra dimensions are ignored.
+	if x, y := New(1, 2, 3, 4, 5).XY(); x != 1 || y != 2 {
+		t.Errorf("XY() on 5D = %d, %d; want 1, 2", x, y)
+	}
+}
+
+func TestXYZW_PanicTooFewDims(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		f    func()
+		want string
+	}{
+		{"XY of 1D", func() { New(1).XY() }, "lattice: XY needs 2 dimensions, Addr[1] has 1"},
+		{"XYZ of 2D", func() { New(1, 2).XYZ() }, "lattice: XYZ needs 3 dimensions, Addr[1 2] has 2"},
+		{"XYZW of 3D", func() { New(1, 2, 3).XYZW() }, "lattice: XYZW needs 4 dimensions, Addr[1 2 3] has 3"},
+		{"XY of empty", func() { New().XY() }, "lattice: XY needs 2 dimensions, Addr[] has 0"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != testCase.want {
+					t.Errorf("panic message = %q, want %q", got, testCase.want)
+				}
+			}()
+
+			testCase.f()
+		})
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestXYZW_ZeroAllocs(t *testing.T) {
+	addr := New(1, 2, 3, 4)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = addr.XY()
+		_, _, _ = addr.XYZ()
+		_, _, _, _ = addr.XYZW()
+	})
+	if allocs != 0 {
+		t.Errorf("XY/XYZ/XYZW allocs = %v, want 0", allocs)
 	}
 }
 
 // ============================================================
-// InRange
+// Contains
 // ============================================================
 
-func TestInRange_Basic(t *testing.T) {
+func TestContains_Basic(t *testing.T) {
 	t.Parallel()
 
-	addr := New(10, 20, 30)
-
 	tests := []struct {
-		name   string
-		ranges []AddrRange
-		want   bool
+		name string
+		a, b []int
+		want bool
 	}{
-		{"all match", []AddrRange{{5, 15}, {15, 25}, {25, 35}}, true},
-		{"first fails", []AddrRange{{15, 20}, {15, 25}, {25, 35}}, false},
-		{"second fails", []AddrRange{{5, 15}, {25, 30}, {25, 35}}, false},
-		{"third fails", []AddrRange{{5, 15}, {15, 25}, {35, 40}}, false},
-		{"any wildcard", []AddrRange{{-1, -1}, {-1, -1}, {-1, -1}}, true},
-		{"exact match", []AddrRange{{10, 10}, {20, 20}, {30, 30}}, true},
-		{"partial ranges", []AddrRange{{5, 15}}, true},
-		{"only min", []AddrRange{{5, -1}, {15, -1}, {25, -1}}, true},
-		{"only max", []AddrRange{{-1, 15}, {-1, 25}, {-1, 35}}, true},
-		{"min fails", []AddrRange{{11, -1}}, false},
-		{"max fails", []AddrRange{{-1, 9}}, false},
+		{"prefix match", []int{1, 2}, []int{1, 2, 3}, true},
+		{"exact match", []int{1, 2, 3}, []int{1, 2, 3}, true},
+		{"empty contains all", []int{}, []int{1, 2, 3}, true},
+		{"longer does not contain shorter", []int{1, 2, 3}, []int{1, 2}, false},
+		{"different values", []int{1, 2}, []int{1, 3, 4}, false},
+		{"single dim prefix", []int{1}, []int{1, 2, 3}, true},
+		{"single dim mismatch", []int{2}, []int{1, 2, 3}, false},
 	}
 
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			got := addr.InRange(testCase.ranges...)
+			got := New(testCase.a...).Contains(New(testCase.b...))
 			if got != testCase.want {
-				t.Errorf("InRange() = %v, want %v", got, testCase.want)
+				t.Errorf("Contains() = %v, want %v", got, testCase.want)
 			}
 		})
 	}
 }
 
-func TestInRange_FewerRangesThanDims(t *testing.T) {
+func TestContains_NotCommutative(t *testing.T) {
 	t.Parallel()
 
-	addr := New(10, 20, 30)
+	aAddr := New(1, 2)
+	bAddr := New(1, 2, 3)
 
-	// Only check first two dimensions
-	if !addr.InRange(AddrRange{5, 15}, AddrRange{15, 25}) {
-		t.Error("expected true with fewer ranges than dims")
+	if !aAddr.Contains(bAddr) {
+		t.Error("a.Contains(b) should be true")
+	}
+
+	if bAddr.Contains(aAddr) {
+		t.Error("b.Contains(a) should be false")
 	}
 }
 
-func TestInRange_MoreRangesThanDims(t *testing.T) {
+func TestContains_LargeValues(t *testing.T) {
 	t.Parallel()
 
-	addr := New(10, 20)
+	aAddr := New(500000, 999999)
+	bAddr := New(500000, 999999, 1048575)
 
-	// Extra ranges are ignored
-	if !addr.InRange(AddrRange{5, 15}, AddrRange{15, 25}, AddrRange{25, 35}) {
-		t.Error("expected true when extra ranges are ignored")
+	if !aAddr.Contains(bAddr) {
+		t.Error("expected a to contain b")
 	}
 }
 
-func TestInRange_BoundaryValues(t *testing.T) {
+func TestContainsStrict(t *testing.T) {
 	t.Parallel()
 
-	addr := New(0, MaxCoordValue)
+	tests := []struct {
+		name string
+		a, b Addr
+		want bool
+	}{
+		{"proper prefix", New(1, 2), New(1, 2, 3), true},
+		{"empty is proper prefix", New(), New(7), true},
+		{"exact match", New(1, 2, 3), New(1, 2, 3), false},
+		{"both empty", New(), New(), false},
+		{"longer receiver", New(1, 2, 3), New(1, 2), false},
+		{"not a prefix", New(1, 3), New(1, 2, 3), false},
+	}
 
-	if !addr.InRange(AddrRange{0, 0}, AddrRange{MaxCoordValue, MaxCoordValue}) {
-		t.Error("expected boundary values to match exactly")
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := testCase.a.ContainsStrict(testCase.b); got != testCase.want {
+				t.Errorf("%v.ContainsStrict(%v) = %v, want %v", testCase.a, testCase.b, got, testCase.want)
+			}
+
+			if got := testCase.a.Contains(testCase.b); got != (testCase.want || testCase.a == testCase.b) {
+				t.Errorf("%v.Contains(%v) = %v", testCase.a, testCase.b, got)
+			}
+		})
+	}
+}
+
+func TestPrefixEqual(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b Addr
+		want bool
+	}{
+		{"a is prefix", New(1, 2), New(1, 2, 3), true},
+		{"b is prefix", New(1, 2, 3), New(1, 2), true},
+		{"identical", New(4, 5, 6), New(4, 5, 6), true},
+		{"empty", New(), New(9, 9), true},
+		{"diverge at last shared", New(1, 2), New(1, 3, 3), false},
+		{"diverge at last shared, same dims", New(1, 2, 3), New(1, 2, 4), false},
+		{"diverge at first", New(0, 2, 3), New(1, 2), false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := testCase.a.PrefixEqual(testCase.b); got != testCase.want {
+				t.Errorf("%v.PrefixEqual(%v) = %v, want %v", testCase.a, testCase.b, got, testCase.want)
+			}
+
+			if got := testCase.b.PrefixEqual(testCase.a); got != testCase.want {
+				t.Errorf("%v.PrefixEqual(%v) = %v, want %v", testCase.b, testCase.a, got, testCase.want)
+			}
+
+			if either := testCase.a.Contains(testCase.b) || testCase.b.Contains(testCase.a); either != testCase.want {
+				t.Errorf("Contains either way = %v, want %v", either, testCase.want)
+			}
+		})
 	}
 }
 
 // ============================================================
-// IsZero
+// CommonPrefix
 // ============================================================
 
-func TestIsZero_Basic(t *testing.T) {
+func TestCommonPrefix(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name   string
-		coords []int
-		want   bool
+		name string
+		a, b Addr
+		want Addr
 	}{
-		{"all zeros", []int{0, 0, 0}, true},
-		{"single zero", []int{0}, true},
-		{"has nonzero", []int{0, 0, 1}, false},
-		{"all nonzero", []int{1, 2, 3}, false},
-		{"first nonzero", []int{1, 0, 0}, false},
+		{"both empty", New(), New(), New()},
+		{"share nothing", New(1, 2, 3), New(4, 2, 3), New()},
+		{"one empty", New(), New(1, 2), New()},
+		{"identical", New(1, 2, 3), New(1, 2, 3), New(1, 2, 3)},
+		{"diverge at last", New(1, 2, 3), New(1, 2, 9), New(1, 2)},
+		{"a contains b", New(1, 2), New(1, 2, 3, 4), New(1, 2)},
+		{"b contains a", New(1, 2, 3, 4), New(1, 2), New(1, 2)},
+		{"later match ignored", New(1, 5, 3), New(1, 6, 3), New(1)},
 	}
 
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			got := New(testCase.coords...).IsZero()
+			got := testCase.a.CommonPrefix(testCase.b)
 			if got != testCase.want {
-				t.Errorf("IsZero() = %v, want %v", got, testCase.want)
+				t.Errorf("CommonPrefix() = %v, want %v", got, testCase.want)
+			}
+
+			if n := testCase.a.CommonPrefixLen(testCase.b); n != testCase.want.Dims() {
+				t.Errorf("CommonPrefixLen() = %d, want %d", n, testCase.want.Dims())
+			}
+
+			if !got.Contains(testCase.a) || !got.Contains(testCase.b) {
+				t.Errorf("prefix %v does not contain both %v and %v", got, testCase.a, testCase.b)
 			}
 		})
 	}
 }
 
-func TestIsZero_EmptyAddr(t *testing.T) {
+// ============================================================
+// EqualCoords
+// ============================================================
+
+func TestEqualCoords(t *testing.T) {
 	t.Parallel()
 
-	// Empty address has no coordinates - all zero vacuously
-	if !New().IsZero() {
-		t.Error("empty address should be zero")
+	tests := []struct {
+		name        string
+		a, b        Addr
+		equalCoords bool
+		equal       bool
+	}{
+		{"same Morton", New(1, 2, 3), New(1, 2, 3), true, true},
+		{"Morton vs Hilbert", New(1, 2, 3), NewHilbert(1, 2, 3), true, false},
+		{"Morton vs signed", New(1, 2, 3), NewSigned(1, 2, 3), true, false},
+		{"Hilbert vs signed", NewHilbert(7, 0), NewSigned(7, 0), true, false},
+		{"same Hilbert", NewHilbert(5, 9), NewHilbert(5, 9), true, true},
+		{"different coords", New(1, 2), NewHilbert(2, 1), false, false},
+		{"different dims", New(1, 2), New(1, 2, 0), false, false},
+		{"negative signed", NewSigned(-1, 2), New(1, 2), false, false},
+		{"empty", New(), NewHilbert(), true, true},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := testCase.a.EqualCoords(testCase.b); got != testCase.equalCoords {
+				t.Errorf("%#v.EqualCoords(%#v) = %v, want %v", testCase.a, testCase.b, got, testCase.equalCoords)
+			}
+
+			if got := testCase.b.EqualCoords(testCase.a); got != testCase.equalCoords {
+				t.Errorf("EqualCoords not symmetric for %#v, %#v", testCase.a, testCase.b)
+			}
+
+			if got := testCase.a.Equal(testCase.b); got != testCase.equal {
+				t.Errorf("%#v.Equal(%#v) = %v, want %v", testCase.a, testCase.b, got, testCase.equal)
+			}
+		})
 	}
 }
 
-func TestIsZero_ZeroValue(t *testing.T) {
-	t.Parallel()
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestEqualCoords_ZeroAllocs(t *testing.T) {
+	a, b := New(1, 2, 3, 4), NewHilbert(1, 2, 3, 4)
 
-	var addr Addr
-	if !addr.IsZero() {
-		t.Error("zero value Addr should be zero")
+	if allocs := testing.AllocsPerRun(100, func() { _ = a.EqualCoords(b) }); allocs != 0 {
+		t.Errorf("EqualCoords allocs = %v, want 0", allocs)
 	}
 }
 
 // ============================================================
-// Slice
+// Compare
 // ============================================================
 
-func TestSlice_Basic(t *testing.T) {
+func TestCompare(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name     string
-		coords   []int
-		from, to int
-		want     []int
+		name string
+		a, b Addr
+		want int
+	}{
+		{"equal", New(1, 2, 3), New(1, 2, 3), 0},
+		{"empty", New(), New(), 0},
+		{"morton x low bit", New(1, 0), New(0, 1), -1},
+		{"morton quadrant", New(1, 1), New(2, 0), -1},
+		{"high bit dominates", New(0, 1<<19), New(MaxCoordValue, 0), 1},
+		{"same index fewer dims first", New(0), New(0, 0), -1},
+		{"high word", New(make([]int, 12)...).With(11, 1<<19), New(make([]int, 12)...), 1},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := testCase.a.Compare(testCase.b); got != testCase.want {
+				t.Errorf("%v.Compare(%v) = %d, want %d", testCase.a, testCase.b, got, testCase.want)
+			}
+
+			if got := testCase.b.Compare(testCase.a); got != -testCase.want {
+				t.Errorf("%v.Compare(%v) = %d, want %d", testCase.b, testCase.a, got, -testCase.want)
+			}
+		})
+	}
+}
+
+func TestCompare_MatchesZIndex(t *testing.T) {
+	t.Parallel()
+
+	var addrs []Addr
+	for a := range Box(New(0, 0, 0), New(7, 7, 7)) {
+		addrs = append(addrs, a)
+	}
+
+	slices.SortFunc(addrs, Addr.Compare)
+
+	for i := 1; i < len(addrs); i++ {
+		prev, _ := addrs[i-1].ZIndex()
+		cur, _ := addrs[i].ZIndex()
+
+		if cur != prev+1 {
+			t.Fatalf("sorted #%d: ZIndex %d follows %d", i, cur, prev)
+		}
+	}
+}
+
+func TestCompareDim(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b Addr
+		dim  int
+		want int
+	}{
+		{"equal", New(1, 5, 9), New(2, 5, 0), 1, 0},
+		{"less", New(9, 1), New(0, 2), 1, -1},
+		{"greater", New(9, 1), New(0, 2), 0, 1},
+		{"mixed dims", New(3), New(3, 4, 5), 0, 0},
+		{"max", New(0, MaxCoordValue), New(0, MaxCoordValue-1), 1, 1},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := testCase.a.CompareDim(testCase.b, testCase.dim); got != testCase.want {
+				t.Errorf("%v.CompareDim(%v, %d) = %d, want %d", testCase.a, testCase.b, testCase.dim, got, testCase.want)
+			}
+
+			if got := testCase.b.CompareDim(testCase.a, testCase.dim); got != -testCase.want {
+				t.Errorf("%v.CompareDim(%v, %d) = %d, want %d", testCase.b, testCase.a, testCase.dim, got, -testCase.want)
+			}
+		})
+	}
+}
+
+func TestCompareDim_Sort(t *testing.T) {
+	t.Parallel()
+
+	addrs := []Addr{New(1, 2, 7), New(5, 5, 3), New(0, 9, 5), New(4, 0, 1)}
+	slices.SortFunc(addrs, func(a, b Addr) int { return a.CompareDim(b, 2) })
+
+	for i := 1; i < len(addrs); i++ {
+		if addrs[i-1].At(2) > addrs[i].At(2) {
+			t.Fatalf("not sorted by z: %v", addrs)
+		}
+	}
+}
+
+func TestCompareDim_PanicOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a, b Addr
+		dim  int
+		want string
+	}{
+		{"beyond receiver", New(1), New(1, 2), 1, "lattice: dimension index 1 out of range [0:1]"},
+		{"beyond argument", New(1, 2), New(1), 1, "lattice: dimension index 1 out of range [0:1]"},
+		{"negative", New(1, 2), New(1, 2), -1, "lattice: dimension index -1 out of range [0:2]"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != testCase.want {
+					t.Errorf("panic message = %q, want %q", got, testCase.want)
+				}
+			}()
+
+			testCase.a.CompareDim(testCase.b, testCase.dim)
+		})
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+	t.Parallel()
+
+	addrs := []Addr{New(1, 0, 5), New(3, 9, 2), New(2, 1, 5), New(1, 4, 2), New(3, 3, 5)}
+	slices.SortFunc(addrs, OrderBy(SortKey{Dim: 2}, SortKey{Dim: 0, Desc: true}))
+
+	want := []Addr{New(3, 9, 2), New(1, 4, 2), New(3, 3, 5), New(2, 1, 5), New(1, 0, 5)}
+	if !slices.Equal(addrs, want) {
+		t.Errorf("ORDER BY z, x DESC = %v, want %v", addrs, want)
+	}
+
+	slices.SortFunc(addrs, OrderBy(SortKey{Dim: 0, Desc: true}, SortKey{Dim: 1}))
+
+	want = []Addr{New(3, 3, 5), New(3, 9, 2), New(2, 1, 5), New(1, 0, 5), New(1, 4, 2)}
+	if !slices.Equal(addrs, want) {
+		t.Errorf("ORDER BY x DESC, y = %v, want %v", addrs, want)
+	}
+}
+
+func TestOrderBy_Ties(t *testing.T) {
+	t.Parallel()
+
+	if got := OrderBy()(New(1), New(2)); got != 0 {
+		t.Errorf("OrderBy() = %d, want 0", got)
+	}
+
+	if got := OrderBy(SortKey{Dim: 0})(New(4, 1), New(4, 2)); got != 0 {
+		t.Errorf("OrderBy(x) on tied x = %d, want 0", got)
+	}
+}
+
+func TestOrderBy_LazyPanic(t *testing.T) {
+	t.Parallel()
+
+	less := OrderBy(SortKey{Dim: 0}, SortKey{Dim: 2})
+
+	// The first key decides, so the missing dimension 2 is never read.
+	if got := less(New(1, 5), New(2, 5)); got != -1 {
+		t.Errorf("comparison = %d, want -1", got)
+	}
+
+	defer func() {
+		if got, want := fmt.Sprintf("%v", recover()), "lattice: dimension index 2 out of range [0:2]"; got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	less(New(1, 5), New(1, 6))
+}
+
+// ============================================================
+// InRange
+// ============================================================
+
+func TestInRange_Basic(t *testing.T) {
+	t.Parallel()
+
+	addr := New(10, 20, 30)
+
+	tests := []struct {
+		name   string
+		ranges []AddrRange
+		want   bool
+	}{
+		{"all match", []AddrRange{{5, 15}, {15, 25}, {25, 35}}, true},
+		{"first fails", []AddrRange{{15, 20}, {15, 25}, {25, 35}}, false},
+		{"second fails", []AddrRange{{5, 15}, {25, 30}, {25, 35}}, false},
+		{"third fails", []AddrRange{{5, 15}, {15, 25}, {35, 40}}, false},
+		{"any wildcard", []AddrRange{{-1, -1}, {-1, -1}, {-1, -1}}, true},
+		{"exact match", []AddrRange{{10, 10}, {20, 20}, {30, 30}}, true},
+		{"partial ranges", []AddrRange{{5, 15}}, true},
+		{"only min", []AddrRange{{5, -1}, {15, -1}, {25, -1}}, true},
+		{"only max", []AddrRange{{-1, 15}, {-1, 25}, {-1, 35}}, true},
+		{"min fails", []AddrRange{{11, -1}}, false},
+		{"max fails", []AddrRange{{-1, 9}}, false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := addr.InRange(testCase.ranges...)
+			if got != testCase.want {
+				t.Errorf("InRange() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestInRange_FewerRangesThanDims(t *testing.T) {
+	t.Parallel()
+
+	addr := New(10, 20, 30)
+
+	// Only check first two dimensions
+	if !addr.InRange(AddrRange{5, 15}, AddrRange{15, 25}) {
+		t.Error("expected true with fewer ranges than dims")
+	}
+}
+
+func TestInRange_MoreRangesThanDims(t *testing.T) {
+	t.Parallel()
+
+	addr := New(10, 20)
+
+	// Extra ranges are ignored
+	if !addr.InRange(AddrRange{5, 15}, AddrRange{15, 25}, AddrRange{25, 35}) {
+		t.Error("expected true when extra ranges are ignored")
+	}
+}
+
+func TestInRange_BoundaryValues(t *testing.T) {
+	t.Parallel()
+
+	addr := New(0, MaxCoordValue)
+
+	if !addr.InRange(AddrRange{0, 0}, AddrRange{MaxCoordValue, MaxCoordValue}) {
+		t.Error("expected boundary values to match exactly")
+	}
+}
+
+func TestInRangeFunc(t *testing.T) {
+	t.Parallel()
+
+	isEven := func(_, v int) bool { return v%2 == 0 }
+	between := func(lo, hi int) func(int, int) bool {
+		return func(_, v int) bool { return v >= lo && v <= hi }
+	}
+	oneOf := func(set ...int) func(int, int) bool {
+		return func(_, v int) bool { return slices.Contains(set, v) }
+	}
+
+	addr := New(4, 7, 30)
+
+	tests := []struct {
+		name  string
+		preds []func(dim, value int) bool
+		want  bool
+	}{
+		{"no predicates", nil, true},
+		{"even first", []func(int, int) bool{isEven}, true},
+		{"even second", []func(int, int) bool{nil, isEven}, false},
+		{"numeric and set", []func(int, int) bool{between(0, 5), oneOf(1, 7), between(25, 35)}, true},
+		{"set miss", []func(int, int) bool{nil, nil, oneOf(1, 2, 3)}, false},
+		{"all nil", []func(int, int) bool{nil, nil, nil}, true},
+		{"beyond dims ignored", []func(int, int) bool{nil, nil, nil, func(int, int) bool { return false }}, true},
+		{"dim index passed", []func(int, int) bool{nil, nil, func(dim, _ int) bool { return dim == 2 }}, true},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := addr.InRangeFunc(testCase.preds...); got != testCase.want {
+				t.Errorf("InRangeFunc() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+// ============================================================
+// Clamp
+// ============================================================
+
+func TestClamp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		addr   Addr
+		ranges []AddrRange
+		want   Addr
+	}{
+		{"no ranges", New(1, 2, 3), nil, New(1, 2, 3)},
+		{"already in range", New(10, 20, 30), []AddrRange{{5, 15}, {15, 25}, {25, 35}}, New(10, 20, 30)},
+		{"below min", New(1, 2), []AddrRange{{5, 15}, {10, 20}}, New(5, 10)},
+		{"above max", New(100, 200), []AddrRange{{5, 15}, {10, 20}}, New(15, 20)},
+		{"at boundaries", New(5, 20), []AddrRange{{5, 15}, {10, 20}}, New(5, 20)},
+		{"wildcards", New(1, 50, 9), []AddrRange{{5, -1}, {-1, 20}, {-1, -1}}, New(5, 20, 9)},
+		{"fewer ranges than dims", New(1, 2, 3), []AddrRange{{5, 5}}, New(5, 2, 3)},
+		{"more ranges than dims", New(1), []AddrRange{{5, 5}, {7, 7}}, New(5)},
+		{"bounds beyond lattice", New(5, 5), []AddrRange{{-5, -3}, {MaxCoordValue + 10, -1}}, New(0, MaxCoordValue)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := testCase.addr.Clamp(testCase.ranges...)
+			if got != testCase.want {
+				t.Errorf("Clamp(%v) = %v, want %v", testCase.ranges, got, testCase.want)
+			}
+		})
+	}
+}
+
+// ============================================================
+// IsZero
+// ============================================================
+
+func TestIsZero_Basic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		coords []int
+		want   bool
+	}{
+		{"all zeros", []int{0, 0, 0}, true},
+		{"single zero", []int{0}, true},
+		{"has nonzero", []int{0, 0, 1}, false},
+		{"all nonzero", []int{1, 2, 3}, false},
+		{"first nonzero", []int{1, 0, 0}, false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := New(testCase.coords...).IsZero()
+			if got != testCase.want {
+				t.Errorf("IsZero() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestIsZero_EmptyAddr(t *testing.T) {
+	t.Parallel()
+
+	// Empty address has no coordinates - all zero vacuously
+	if !New().IsZero() {
+		t.Error("empty address should be zero")
+	}
+}
+
+func TestIsZero_ZeroValue(t *testing.T) {
+	t.Parallel()
+
+	var addr Addr
+	if !addr.IsZero() {
+		t.Error("zero value Addr should be zero")
+	}
+}
+
+// ============================================================
+// Slice
+// ============================================================
+
+func TestSlice_Basic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		coords   []int
+		from, to int
+		want     []int
+	}{
+		{"first two", []int{1, 2, 3, 4}, 0, 2, []int{1, 2}},
+		{"last two", []int{1, 2, 3, 4}, 2, 4, []int{3, 4}},
+		{"middle", []int{1, 2, 3, 4, 5}, 1, 4, []int{2, 3, 4}},
+		{"all", []int{1, 2, 3}, 0, 3, []int{1, 2, 3}},
+		{"single", []int{1, 2, 3}, 1, 2, []int{2}},
+		{"empty slice", []int{1, 2, 3}, 1, 1, []int{}},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			addr := New(testCase.coords...).Slice(testCase.from, testCase.to)
+			got, dims := addr.Coords()
+
+			if dims != len(testCase.want) {
+				t.Fatalf("dims = %d, want %d", dims, len(testCase.want))
+			}
+
+			for i := range dims {
+				if i < len(got) && i < len(testCase.want) && got[i] != testCase.want[i] {
+					t.Errorf("coord[%d] = %d, want %d", i, got[i], testCase.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSlice_PanicFromNegative(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic with negative from")
+		}
+	}()
+
+	New(1, 2, 3).Slice(-1, 2)
+}
+
+func TestSlice_PanicToOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic with to out of range")
+		}
+	}()
+
+	New(1, 2, 3).Slice(0, 4)
+}
+
+func TestSlice_PanicFromGreaterThanTo(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic with from > to")
+		}
+	}()
+
+	New(1, 2, 3).Slice(2, 1)
+}
+
+func TestSlice_PanicMessage(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		rec := recover()
+		if rec == nil {
+			t.Error("expected panic")
+
+			return
+		}
+
+		want := "lattice: slice [1:5] out of range [0:3]"
+		if got := fmt.Sprintf("%v", rec); got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	New(1, 2, 3).Slice(1, 5)
+}
+
+func TestSlice_PreservesOriginal(t *testing.T) {
+	t.Parallel()
+
+	original := New(1, 2, 3, 4, 5)
+	_ = original.Slice(1, 3)
+
+	if original.Dims() != 5 {
+		t.Errorf("original dims = %d, want 5", original.Dims())
+	}
+}
+
+func TestSlice_MatchesReencode(t *testing.T) {
+	t.Parallel()
+
+	addrs := []Addr{
+		New(1, 2, 3),
+		New(MaxCoordValue, 0, MaxCoordValue, 0, MaxCoordValue),
+		New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12),
+		New(
+			MaxCoordValue, MaxCoordValue, MaxCoordValue, MaxCoordValue,
+			MaxCoordValue, MaxCoordValue, MaxCoordValue, MaxCoordValue,
+			MaxCoordValue, MaxCoordValue, MaxCoordValue, MaxCoordValue,
+		),
+	}
+
+	for _, addr := range addrs {
+		coords, dims := addr.Coords()
+
+		for from := 0; from <= dims; from++ {
+			for to := from; to <= dims; to++ {
+				want := New(coords[from:to]...)
+
+				if got := addr.Slice(from, to); got != want {
+					t.Errorf("%v.Slice(%d, %d) = %v, want %v", addr, from, to, got, want)
+				}
+			}
+		}
+	}
+}
+
+// ============================================================
+// With
+// ============================================================
+
+func TestWith_Basic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		coords []int
+		dimIdx int
+		value  int
+		want   []int
+	}{
+		{"replace first", []int{1, 2, 3}, 0, 99, []int{99, 2, 3}},
+		{"replace middle", []int{1, 2, 3}, 1, 99, []int{1, 99, 3}},
+		{"replace last", []int{1, 2, 3}, 2, 99, []int{1, 2, 99}},
+		{"replace with zero", []int{1, 2, 3}, 1, 0, []int{1, 0, 3}},
+		{"replace with max", []int{1, 2, 3}, 1, MaxCoordValue, []int{1, MaxCoordValue, 3}},
+		{"replace single dim", []int{42}, 0, 99, []int{99}},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			addr := New(testCase.coords...).With(testCase.dimIdx, testCase.value)
+			got, dims := addr.Coords()
+
+			if dims != len(testCase.want) {
+				t.Fatalf("dims = %d, want %d", dims, len(testCase.want))
+			}
+
+			for i := range dims {
+				if i < len(got) && i < len(testCase.want) && got[i] != testCase.want[i] {
+					t.Errorf("coord[%d] = %d, want %d", i, got[i], testCase.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWith_PanicNegativeIndex(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic with negative index")
+		}
+	}()
+
+	New(1, 2, 3).With(-1, 99)
+}
+
+func TestWith_PanicIndexOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic with index out of range")
+		}
+	}()
+
+	New(1, 2, 3).With(3, 99)
+}
+
+func TestWith_PanicMessage(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		rec := recover()
+
+		if rec == nil {
+			t.Error("expected panic")
+
+			return
+		}
+
+		want := "lattice: dimension index 5 out of range [0:3]"
+		if got := fmt.Sprintf("%v", rec); got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	New(1, 2, 3).With(5, 99)
+}
+
+func TestWith_PreservesOriginal(t *testing.T) {
+	t.Parallel()
+
+	original := New(1, 2, 3)
+	_ = original.With(1, 99)
+
+	if original.At(1) != 2 {
+		t.Errorf("original coord[1] = %d, want 2", original.At(1))
+	}
+}
+
+func TestWith_Chaining(t *testing.T) {
+	t.Parallel()
+
+	addr := New(0, 0, 0).
+		With(0, 10).
+		With(1, 20).
+		With(2, 30)
+
+	want := []int{10, 20, 30}
+	got, dims := addr.Coords()
+
+	if dims != len(want) {
+		t.Fatalf("dims = %d, want %d", dims, len(want))
+	}
+
+	for dimIx := range dims {
+		if dimIx < len(got) && dimIx < len(want) {
+			if got[dimIx] != want[dimIx] {
+				t.Errorf("coord[%d] = %d, want %d", dimIx, got[dimIx], want[dimIx])
+			}
+		} else {
+			t.Fatalf("index out of range")
+		}
+	}
+}
+
+func TestWith_PanicValueOutOfRange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		value   int
+		wantMsg string
+	}{
+		{"negative", -1, fmt.Sprintf("lattice: coord[1]=-1 out of range [0,%d]", MaxCoordValue)},
+		{"too large", MaxCoordValue + 1, fmt.Sprintf("lattice: coord[1]=%d out of range [0,%d]", MaxCoordValue+1, MaxCoordValue)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				rec := recover()
+
+				if rec == nil {
+					t.Error("expected panic")
+
+					return
+				}
+
+				if got := fmt.Sprintf("%v", rec); got != testCase.wantMsg {
+					t.Errorf("panic message = %q, want %q", got, testCase.wantMsg)
+				}
+			}()
+
+			New(1, 2, 3).With(1, testCase.value)
+		})
+	}
+}
+
+func TestWith_MatchesReencode(t *testing.T) {
+	t.Parallel()
+
+	addrs := []Addr{
+		New(0),
+		New(1, 2, 3),
+		New(MaxCoordValue, MaxCoordValue, MaxCoordValue),
+		New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12),
+		New(MaxCoordValue, 0, MaxCoordValue, 0, MaxCoordValue, 0, MaxCoordValue, 0, MaxCoordValue, 0, MaxCoordValue, 0),
+	}
+
+	values := []int{0, 1, 0xAAAAA, 0x55555, MaxCoordValue}
+
+	for _, addr := range addrs {
+		for dimIdx := range addr.Dims() {
+			for _, value := range values {
+				got := addr.With(dimIdx, value)
+				want := withReencode(addr, dimIdx, value)
+
+				if got != want {
+					t.Errorf("%v.With(%d, %d) = %v, want %v", addr, dimIdx, value, got, want)
+				}
+			}
+		}
+	}
+}
+
+// withReencode is the decode, mutate and re-encode reference for With.
+func withReencode(addr Addr, dimIdx, value int) Addr {
+	var buf Buffer
+
+	coords := addr.CoordsSlice(buf[:])
+	coords[dimIdx] = value
+
+	return New(coords...)
+}
+
+// ============================================================
+// Words / FromWords
+// ============================================================
+
+func TestWords_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	addrs := []Addr{
+		{},
+		New(),
+		New(1, 2, 3),
+		New(MaxCoordValue, MaxCoordValue, MaxCoordValue, MaxCoordValue),
+		New(
+			MaxCoordValue, MaxCoordValue, MaxCoordValue, MaxCoordValue,
+			MaxCoordValue, MaxCoordValue, MaxCoordValue, MaxCoordValue,
+			MaxCoordValue, MaxCoordValue, MaxCoordValue, MaxCoordValue,
+		),
+	}
+
+	for _, addr := range addrs {
+		got, err := FromWords(addr.Words())
+		if err != nil {
+			t.Fatalf("FromWords(%v) error = %v", addr, err)
+		}
+
+		if !got.Equal(addr) {
+			t.Errorf("FromWords(Words()) = %v, want %v", got, addr)
+		}
+	}
+}
+
+func TestFromWords_Rejects(t *testing.T) {
+	t.Parallel()
+
+	w3 := New(1, 2, 3).Words()
+	w4 := New(1, 2, 3, 4).Words()
+
+	tests := []struct {
+		name  string
+		words [4]uint64
+	}{
+		{"dims header 13", [4]uint64{13}},
+		{"dims header 15", [4]uint64{15}},
+		{"empty with payload bit", [4]uint64{0 | 1<<4}},
+		{"3D with bit 64", [4]uint64{w3[0], 1, 0, 0}},
+		{"4D with bit 84", [4]uint64{w4[0], w4[1] | 1<<20, 0, 0}},
+		{"1D with high word", [4]uint64{1, 0, 0, 1 << 40}},
+		{"12D with bit 253", [4]uint64{12, 0, 0, 1 << 61}},
+		{"12D with bit 244", [4]uint64{12, 0, 0, 1 << 52}},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := FromWords(testCase.words); !errors.Is(err, ErrMalformed) {
+				t.Errorf("FromWords(%x) error = %v, want %v", testCase.words, err, ErrMalformed)
+			}
+		})
+	}
+}
+
+func TestIsValid(t *testing.T) {
+	t.Parallel()
+
+	maxDims := make([]int, MaxDimensions)
+	for i := range maxDims {
+		maxDims[i] = MaxCoordValue
+	}
+
+	tests := []struct {
+		name string
+		addr Addr
+		want bool
+	}{
+		{"zero value", Addr{}, true},
+		{"new", New(1, 2, 3), true},
+		{"max dims max values", New(maxDims...), true},
+		{"hilbert", NewHilbert(maxDims...), true},
+		{"signed", NewSigned(-1, 1), true},
+		{"stray high bit", Addr{3, 0, 0, 1 << 40}, false},
+		{"stray bit after 3D region", Addr{New(1, 2, 3)[0], 1, 0, 0}, false},
+		{"dims header 13", Addr{13}, false},
+		{"dims header 15", Addr{15}, false},
+		{"unknown flag bit", Addr{12, 0, 0, 1 << 61}, false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := testCase.addr.IsValid(); got != testCase.want {
+				t.Errorf("%x.IsValid() = %v, want %v", testCase.addr, got, testCase.want)
+			}
+
+			if _, err := FromWords(testCase.addr.Words()); (err == nil) != testCase.want {
+				t.Errorf("FromWords(%x) error = %v, disagrees with IsValid", testCase.addr, err)
+			}
+		})
+	}
+}
+
+func TestDimsValid(t *testing.T) {
+	t.Parallel()
+
+	corrupted := New(1, 2, 3)
+	corrupted[2] |= 1 << 7 // beyond the 64 bits a 3D address uses
+
+	tests := []struct {
+		name     string
+		addr     Addr
+		wantDims int
+		wantOK   bool
 	}{
-		{"first two", []int{1, 2, 3, 4}, 0, 2, []int{1, 2}},
-		{"last two", []int{1, 2, 3, 4}, 2, 4, []int{3, 4}},
-		{"middle", []int{1, 2, 3, 4, 5}, 1, 4, []int{2, 3, 4}},
-		{"all", []int{1, 2, 3}, 0, 3, []int{1, 2, 3}},
-		{"single", []int{1, 2, 3}, 1, 2, []int{2}},
-		{"empty slice", []int{1, 2, 3}, 1, 1, []int{}},
+		{"zero value", Addr{}, 0, true},
+		{"3D", New(1, 2, 3), 3, true},
+		{"Hilbert 12D", NewHilbert(make([]int, MaxDimensions)...), MaxDimensions, true},
+		{"corrupted 3D", corrupted, 3, false},
+		{"header claims 1D with 2D bits", Addr{1 | New(0, 1<<19)[0]&^dimsMask}, 1, false},
+		{"header 14", Addr{14}, 14, false},
 	}
 
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			addr := New(testCase.coords...).Slice(testCase.from, testCase.to)
-			got, dims := addr.Coords()
-
-			if dims != len(testCase.want) {
-				t.Fatalf("dims = %d, want %d", dims, len(testCase.want))
+			dims, ok := testCase.addr.DimsValid()
+			if dims != testCase.wantDims || ok != testCase.wantOK {
+				t.Errorf("%x.DimsValid() = %d, %v; want %d, %v", testCase.addr, dims, ok, testCase.wantDims, testCase.wantOK)
 			}
 
-			for i := range dims {
-				if i < len(got) && i < len(testCase.want) && got[i] != testCase.want[i] {
-					t.Errorf("coord[%d] = %d, want %d", i, got[i], testCase.want[i])
-				}
+			if ok != testCase.addr.IsValid() {
+				t.Errorf("DimsValid ok = %v disagrees with IsValid", ok)
 			}
 		})
 	}
 }
 
-func TestSlice_PanicFromNegative(t *testing.T) {
-	t.Parallel()
-
-	defer func() {
-		if r := recover(); r == nil {
-			t.Error("expected panic with negative from")
-		}
-	}()
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestIsValid_ZeroAllocs(t *testing.T) {
+	good, bad := New(1, 2, 3), Addr{3, 0, 0, 1 << 40}
 
-	New(1, 2, 3).Slice(-1, 2)
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = good.IsValid()
+		_ = bad.IsValid()
+		_, _ = bad.DimsValid()
+	})
+	if allocs != 0 {
+		t.Errorf("IsValid allocs = %v, want 0", allocs)
+	}
 }
 
-func TestSlice_PanicToOutOfRange(t *testing.T) {
-	t.Parallel()
-
-	defer func() {
-		if r := recover(); r == nil {
-			t.Error("expected panic with to out of range")
-		}
-	}()
-
-	New(1, 2, 3).Slice(0, 4)
-}
+// ============================================================
+// Canonical
+// ============================================================
 
-func TestSlice_PanicFromGreaterThanTo(t *testing.T) {
+func TestCanonical_ZeroDims(t *testing.T) {
 	t.Parallel()
 
-	defer func() {
-		if r := recover(); r == nil {
-			t.Error("expected panic with from > to")
-		}
-	}()
+	if New().Canonical() != (Addr{}).Canonical() {
+		t.Fatalf("New().Canonical() = %x, want %x", New().Canonical(), (Addr{}).Canonical())
+	}
 
-	New(1, 2, 3).Slice(2, 1)
-}
+	parsed, _ := Parse("Addr[]")
+	fromKey, _ := ParseKey(New().Key())
+	fromZ, _ := FromZIndex(0, 0)
+	fromWords, _ := FromWords([4]uint64{})
 
-func TestSlice_PanicMessage(t *testing.T) {
-	t.Parallel()
+	var unmarshaled, unmarshaledFlagged Addr
+	if err := unmarshaled.UnmarshalBinary([]byte{binaryVersion, 0}); err != nil {
+		t.Fatal(err)
+	}
 
-	defer func() {
-		rec := recover()
-		if rec == nil {
-			t.Error("expected panic")
+	if err := unmarshaledFlagged.UnmarshalBinary([]byte{binaryVersion, binaryFlagsMask}); err != nil {
+		t.Fatal(err)
+	}
 
-			return
+	zeros := map[string]Addr{
+		"New()":                   New(),
+		"New(1).Parent()":         New(1).Parent(),
+		"Slice(1, 1)":             New(1, 2).Slice(1, 1),
+		"Truncate(0)":             New(1, 2, 3).Truncate(0),
+		"SetDims(0, 0)":           New(4).SetDims(0, 0),
+		"CommonPrefix":            New(1, 2).CommonPrefix(New(3, 4)),
+		"Reverse":                 New().Reverse(),
+		"Parse":                   parsed,
+		"ParseKey":                fromKey,
+		"FromZIndex":              fromZ,
+		"FromWords":               fromWords,
+		"UnmarshalBinary":         unmarshaled,
+		"Canonical of stray bits": Addr{0, 1, 2, 3}.Canonical(),
+		"Canonical of flags":      Addr{0, 0, 0, flagsMask}.Canonical(),
+		"NewSigned()":             NewSigned(),
+		"NewHilbert()":            NewHilbert(),
+		"UnmarshalBinary flagged": unmarshaledFlagged,
+		"signed Truncate(0)":      NewSigned(-1, 2).Truncate(0),
+	}
+
+	for name, a := range zeros {
+		if a != (Addr{}) {
+			t.Errorf("%s = %x, want the zero Addr", name, a)
 		}
 
-		want := "lattice: slice [1:5] out of range [0:3]"
-		if got := fmt.Sprintf("%v", rec); got != want {
-			t.Errorf("panic message = %q, want %q", got, want)
+		if a.Hash() != (Addr{}).Hash() {
+			t.Errorf("%s hashes differently from the zero Addr", name)
 		}
-	}()
 
-	New(1, 2, 3).Slice(1, 5)
+		if a.Canonical() != a {
+			t.Errorf("%s is not canonical", name)
+		}
+	}
 }
 
-func TestSlice_PreservesOriginal(t *testing.T) {
+func TestCanonical_ClearsStrayBits(t *testing.T) {
 	t.Parallel()
 
-	original := New(1, 2, 3, 4, 5)
-	_ = original.Slice(1, 3)
-
-	if original.Dims() != 5 {
-		t.Errorf("original dims = %d, want 5", original.Dims())
-	}
-}
-
-// ============================================================
-// With
-// ============================================================
+	strayInWord := New(7)
+	strayInWord[0] |= 1 << 30
 
-func TestWith_Basic(t *testing.T) {
-	t.Parallel()
+	straySigned := NewSigned(1, 2)
+	straySigned[2] |= 1
 
 	tests := []struct {
-		name   string
-		coords []int
-		dimIdx int
-		value  int
-		want   []int
+		name string
+		a    Addr
+		want Addr
 	}{
-		{"replace first", []int{1, 2, 3}, 0, 99, []int{99, 2, 3}},
-		{"replace middle", []int{1, 2, 3}, 1, 99, []int{1, 99, 3}},
-		{"replace last", []int{1, 2, 3}, 2, 99, []int{1, 2, 99}},
-		{"replace with zero", []int{1, 2, 3}, 1, 0, []int{1, 0, 3}},
-		{"replace with max", []int{1, 2, 3}, 1, MaxCoordValue, []int{1, MaxCoordValue, 3}},
-		{"replace single dim", []int{42}, 0, 99, []int{99}},
+		{"already canonical", New(1, 2, 3), New(1, 2, 3)},
+		{"stray in next word", Addr{New(1, 2, 3)[0], 1, 0, 0}, New(1, 2, 3)},
+		{"stray in same word", strayInWord, New(7)},
+		{"stray high bit", Addr{3, 0, 0, 1 << 40}, New(0, 0, 0)},
+		{"flags kept", NewHilbert(1, 2), NewHilbert(1, 2)},
+		{"flags kept, stray cleared", straySigned, NewSigned(1, 2)},
+		{"12D untouched", New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12), New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)},
 	}
 
 	for _, testCase := range tests {
 		t.Run(testCase.name, func(t *testing.T) {
 			t.Parallel()
 
-			addr := New(testCase.coords...).With(testCase.dimIdx, testCase.value)
-			got, dims := addr.Coords()
-
-			if dims != len(testCase.want) {
-				t.Fatalf("dims = %d, want %d", dims, len(testCase.want))
+			got := testCase.a.Canonical()
+			if got != testCase.want {
+				t.Errorf("%x.Canonical() = %x, want %x", testCase.a, got, testCase.want)
 			}
 
-			for i := range dims {
-				if i < len(got) && i < len(testCase.want) && got[i] != testCase.want[i] {
-					t.Errorf("coord[%d] = %d, want %d", i, got[i], testCase.want[i])
-				}
+			if !got.IsValid() {
+				t.Errorf("%x.Canonical() = %x is not valid", testCase.a, got)
 			}
 		})
 	}
 }
 
-func TestWith_PanicNegativeIndex(t *testing.T) {
-	t.Parallel()
-
-	defer func() {
-		if r := recover(); r == nil {
-			t.Error("expected panic with negative index")
-		}
-	}()
-
-	New(1, 2, 3).With(-1, 99)
-}
-
-func TestWith_PanicIndexOutOfRange(t *testing.T) {
-	t.Parallel()
-
-	defer func() {
-		if r := recover(); r == nil {
-			t.Error("expected panic with index out of range")
-		}
-	}()
-
-	New(1, 2, 3).With(3, 99)
-}
+// ============================================================
+// WithCoords
+// ============================================================
 
-func TestWith_PanicMessage(t *testing.T) {
+func TestWithCoords(t *testing.T) {
 	t.Parallel()
 
-	defer func() {
-		rec := recover()
+	tests := []struct {
+		name  string
+		addr  Addr
+		pairs [][2]int
+		want  Addr
+	}{
+		{"no pairs", New(1, 2, 3), nil, New(1, 2, 3)},
+		{"one pair", New(1, 2, 3), [][2]int{{1, 99}}, New(1, 99, 3)},
+		{"all dims", New(0, 0, 0), [][2]int{{0, 10}, {1, 20}, {2, 30}}, New(10, 20, 30)},
+		{"overlapping last wins", New(1, 2, 3), [][2]int{{1, 5}, {1, 6}, {1, 7}}, New(1, 7, 3)},
+		{"extremes", New(5, 5), [][2]int{{0, 0}, {1, MaxCoordValue}}, New(0, MaxCoordValue)},
+	}
 
-		if rec == nil {
-			t.Error("expected panic")
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
 
-			return
-		}
+			got := testCase.addr.WithCoords(testCase.pairs...)
+			if got != testCase.want {
+				t.Errorf("WithCoords(%v) = %v, want %v", testCase.pairs, got, testCase.want)
+			}
 
-		want := "lattice: dimension index 5 out of range [0:3]"
-		if got := fmt.Sprintf("%v", rec); got != want {
-			t.Errorf("panic message = %q, want %q", got, want)
-		}
-	}()
+			chained := testCase.addr
+			for _, pair := range testCase.pairs {
+				chained = chained.With(pair[0], pair[1])
+			}
 
-	New(1, 2, 3).With(5, 99)
+			if got != chained {
+				t.Errorf("WithCoords(%v) = %v, chained With = %v", testCase.pairs, got, chained)
+			}
+		})
+	}
 }
 
-func TestWith_PreservesOriginal(t *testing.T) {
+func TestWithCoords_PanicMessage(t *testing.T) {
 	t.Parallel()
 
-	original := New(1, 2, 3)
-	_ = original.With(1, 99)
-
-	if original.At(1) != 2 {
-		t.Errorf("original coord[1] = %d, want 2", original.At(1))
+	tests := []struct {
+		name    string
+		pairs   [][2]int
+		wantMsg string
+	}{
+		{"negative index", [][2]int{{-1, 5}}, "lattice: dimension index -1 out of range [0:3]"},
+		{"index too large", [][2]int{{0, 5}, {3, 5}}, "lattice: dimension index 3 out of range [0:3]"},
+		{"negative value", [][2]int{{2, -1}}, fmt.Sprintf("lattice: coord[2]=-1 out of range [0,%d]", MaxCoordValue)},
+		{
+			"value too large",
+			[][2]int{{0, MaxCoordValue + 1}},
+			fmt.Sprintf("lattice: coord[0]=%d out of range [0,%d]", MaxCoordValue+1, MaxCoordValue),
+		},
 	}
-}
 
-func TestWith_Chaining(t *testing.T) {
-	t.Parallel()
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
 
-	addr := New(0, 0, 0).
-		With(0, 10).
-		With(1, 20).
-		With(2, 30)
+			defer func() {
+				rec := recover()
 
-	want := []int{10, 20, 30}
-	got, dims := addr.Coords()
+				if rec == nil {
+					t.Error("expected panic")
 
-	if dims != len(want) {
-		t.Fatalf("dims = %d, want %d", dims, len(want))
-	}
+					return
+				}
 
-	for dimIx := range dims {
-		if dimIx < len(got) && dimIx < len(want) {
-			if got[dimIx] != want[dimIx] {
-				t.Errorf("coord[%d] = %d, want %d", dimIx, got[dimIx], want[dimIx])
-			}
-		} else {
-			t.Fatalf("index out of range")
-		}
+				if got := fmt.Sprintf("%v", rec); got != testCase.wantMsg {
+					t.Errorf("panic message = %q, want %q", got, testCase.wantMsg)
+				}
+			}()
+
+			New(1, 2, 3).WithCoords(testCase.pairs...)
+		})
 	}
 }
 
@@ -1319,6 +3006,17 @@ func BenchmarkAppend_One(b *testing.B) {
 	}
 }
 
+func BenchmarkAppendBuf_One(b *testing.B) {
+	addr := New(1, 2, 3)
+	buf := make([]int, 0, MaxDimensions)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_ = addr.AppendBuf(buf, 4)
+	}
+}
+
 func BenchmarkAt(b *testing.B) {
 	addr := New(10, 20, 30, 40, 50)
 
@@ -1329,6 +3027,37 @@ func BenchmarkAt(b *testing.B) {
 	}
 }
 
+func BenchmarkAt_12D(b *testing.B) {
+	addr := New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)
+
+	b.ResetTimer()
+
+	for i := 0; b.Loop(); i++ {
+		_ = addr.At(0)
+	}
+}
+
+func BenchmarkDecodeDim_12D(b *testing.B) {
+	addr := New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)
+
+	b.ResetTimer()
+
+	for i := 0; b.Loop(); i++ {
+		_ = addr.DecodeDim(0)
+	}
+}
+
+func BenchmarkCoordsAt_12D(b *testing.B) {
+	addr := New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)
+
+	b.ResetTimer()
+
+	for i := 0; b.Loop(); i++ {
+		coords, _ := addr.Coords()
+		_ = coords[0]
+	}
+}
+
 func BenchmarkContains(b *testing.B) {
 	aAddr := New(1, 2)
 	bAddr := New(1, 2, 3, 4, 5)
@@ -1360,6 +3089,16 @@ func BenchmarkSlice(b *testing.B) {
 	}
 }
 
+func BenchmarkSlice_Prefix(b *testing.B) {
+	addr := New(1, 2, 3, 4, 5, 6)
+
+	b.ResetTimer()
+
+	for i := 0; b.Loop(); i++ {
+		_ = addr.Slice(0, 3)
+	}
+}
+
 func BenchmarkWith(b *testing.B) {
 	addr := New(1, 2, 3, 4, 5)
 
@@ -1369,3 +3108,23 @@ func BenchmarkWith(b *testing.B) {
 		_ = addr.With(2, 99)
 	}
 }
+
+func BenchmarkWithCoords_3(b *testing.B) {
+	addr := New(1, 2, 3, 4, 5)
+
+	b.ResetTimer()
+
+	for i := 0; b.Loop(); i++ {
+		_ = addr.WithCoords([2]int{0, 10}, [2]int{1, 20}, [2]int{2, 30})
+	}
+}
+
+func BenchmarkWith_Reencode(b *testing.B) {
+	addr := New(1, 2, 3, 4, 5)
+
+	b.ResetTimer()
+
+	for i := 0; b.Loop(); i++ {
+		_ = withReencode(addr, 2, 99)
+	}
+}
@@ -0,0 +1,179 @@
+package lattice
+
+import (
+	"fmt"
+	"iter"
+	"maps"
+	"slices"
+)
+
+// Map is a thin typed wrapper around map[Addr]V.
+// The zero value is an empty map ready to use. Lookups by Addr key
+// allocate nothing, exactly as with the underlying built-in map.
+// A Map must not be copied after first use.
+type Map[V any] struct {
+	m map[Addr]V
+}
+
+// Get returns the value stored at a and whether it was present.
+func (m *Map[V]) Get(a Addr) (V, bool) {
+	v, ok := m.m[a]
+
+	return v, ok
+}
+
+// GetOrZero returns the value stored at a, or the zero V if absent.
+func (m *Map[V]) GetOrZero(a Addr) V {
+	return m.m[a]
+}
+
+// Set stores v at a, replacing any previous value.
+func (m *Map[V]) Set(a Addr, v V) {
+	if m.m == nil {
+		m.m = make(map[Addr]V)
+	}
+
+	m.m[a] = v
+}
+
+// Delete removes the value stored at a, if any.
+func (m *Map[V]) Delete(a Addr) {
+	delete(m.m, a)
+}
+
+// Len returns the number of stored addresses.
+func (m *Map[V]) Len() int {
+	return len(m.m)
+}
+
+// Clone returns an independent copy of m: later Set or Delete calls on
+// either map do not affect the other. The copy is shallow, so values that
+// are pointers, slices or maps still share their underlying data.
+func (m *Map[V]) Clone() *Map[V] {
+	return &Map[V]{m: maps.Clone(m.m)}
+}
+
+// Equal reports whether m and o hold the same addresses with values that
+// eq considers equal.
+func (m *Map[V]) Equal(o *Map[V], eq func(a, b V) bool) bool {
+	return maps.EqualFunc(m.m, o.m, eq)
+}
+
+// Number is the set of built-in integer and floating-point types.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// SumMap accumulates numeric values into sparse cells keyed by Addr.
+// The zero value is an empty map ready to use.
+// A SumMap must not be copied after first use.
+type SumMap[V Number] struct {
+	m map[Addr]V
+}
+
+// Add adds delta to the value at a. A missing cell starts at delta.
+func (m *SumMap[V]) Add(a Addr, delta V) {
+	if m.m == nil {
+		m.m = make(map[Addr]V)
+	}
+
+	m.m[a] += delta
+}
+
+// Inc adds one to the value at a.
+func (m *SumMap[V]) Inc(a Addr) {
+	m.Add(a, 1)
+}
+
+// Set replaces the value at a with v.
+func (m *SumMap[V]) Set(a Addr, v V) {
+	if m.m == nil {
+		m.m = make(map[Addr]V)
+	}
+
+	m.m[a] = v
+}
+
+// Get returns the value at a, or zero if nothing was accumulated there.
+func (m *SumMap[V]) Get(a Addr) V {
+	return m.m[a]
+}
+
+// Len returns the number of cells holding a value.
+func (m *SumMap[V]) Len() int {
+	return len(m.m)
+}
+
+// Total returns the sum of all cells in a single pass without allocating.
+func (m *SumMap[V]) Total() V {
+	var total V
+	for _, v := range m.m {
+		total += v
+	}
+
+	return total
+}
+
+// NewMap returns an empty built-in map[Addr]V with room for sizeHint
+// entries, so inserting that many never rehashes. It is make with the
+// key type spelled out; pair it with Fill for bulk construction.
+func NewMap[V any](sizeHint int) map[Addr]V {
+	return make(map[Addr]V, sizeHint)
+}
+
+// Fill stores values[i] at addrs[i] in m for every i, overwriting any
+// existing entries; a later duplicate address wins.
+// Panics if addrs and values differ in length.
+func Fill[V any](m map[Addr]V, addrs []Addr, values []V) {
+	if len(addrs) != len(values) {
+		panic(fmt.Sprintf("lattice: Fill got %d addresses and %d values", len(addrs), len(values)))
+	}
+
+	for i, a := range addrs {
+		m[a] = values[i]
+	}
+}
+
+// Entry pairs an address with its value. It is the element type of
+// Entries and KNearest results.
+type Entry[V any] struct {
+	Addr  Addr
+	Value V
+}
+
+// Entries yields every entry of m as an Entry, in unspecified map order.
+// Nothing is allocated per entry.
+func Entries[V any](m map[Addr]V) iter.Seq[Entry[V]] {
+	return func(yield func(Entry[V]) bool) {
+		for a, v := range m {
+			if !yield(Entry[V]{Addr: a, Value: v}) {
+				return
+			}
+		}
+	}
+}
+
+// SortedEntries yields the entries of m in Compare order, which is Morton
+// (Z-order) order among keys of one dimension count, for deterministic,
+// cache-friendly traversal. Each iteration collects and sorts the keys
+// once, so it allocates a single O(len(m)) key slice; values are read
+// from m as they are yielded. Keys deleted from m during iteration are
+// skipped.
+func SortedEntries[V any](m map[Addr]V) iter.Seq2[Addr, V] {
+	return func(yield func(Addr, V) bool) {
+		keys := slices.SortedFunc(maps.Keys(m), Addr.Compare)
+
+		for _, a := range keys {
+			v, ok := m[a]
+			if !ok {
+				continue
+			}
+
+			if !yield(a, v) {
+				return
+			}
+		}
+	}
+}
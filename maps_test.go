@@ -0,0 +1,442 @@
+package lattice_test
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/aclivo/lattice"
+)
+
+// ============================================================
+// Map[V]
+// ============================================================
+
+func TestMapType_SetGet(t *testing.T) {
+	t.Parallel()
+
+	var cells lattice.Map[float64]
+
+	addr1 := lattice.New(1, 2, 3)
+	addr2 := lattice.New(4, 5, 6)
+	addr3 := lattice.New(1, 2, 3) // Same as addr1
+
+	cells.Set(addr1, 1.0)
+	cells.Set(addr2, 2.0)
+	cells.Set(addr3, 3.0) // Overwrites addr1
+
+	if cells.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", cells.Len())
+	}
+
+	if v, ok := cells.Get(addr1); !ok || v != 3.0 {
+		t.Errorf("Get(addr1) = %f, %v, want 3.0, true", v, ok)
+	}
+
+	if v, ok := cells.Get(addr2); !ok || v != 2.0 {
+		t.Errorf("Get(addr2) = %f, %v, want 2.0, true", v, ok)
+	}
+}
+
+func TestMapType_NotFound(t *testing.T) {
+	t.Parallel()
+
+	var cells lattice.Map[float64]
+
+	if _, ok := cells.Get(lattice.New(1)); ok {
+		t.Error("zero Map: expected key not to be found")
+	}
+
+	cells.Set(lattice.New(1, 2, 3), 42.0)
+
+	if v, ok := cells.Get(lattice.New(9, 9, 9)); ok || v != 0 {
+		t.Errorf("Get(missing) = %f, %v, want 0, false", v, ok)
+	}
+
+	if v := cells.GetOrZero(lattice.New(9, 9, 9)); v != 0 {
+		t.Errorf("GetOrZero(missing) = %f, want 0", v)
+	}
+
+	if v := cells.GetOrZero(lattice.New(1, 2, 3)); v != 42.0 {
+		t.Errorf("GetOrZero(present) = %f, want 42.0", v)
+	}
+}
+
+func TestMapType_Delete(t *testing.T) {
+	t.Parallel()
+
+	var cells lattice.Map[string]
+
+	cells.Delete(lattice.New(1)) // no-op on zero Map
+
+	addr := lattice.New(1, 2, 3)
+	cells.Set(addr, "x")
+	cells.Delete(addr)
+
+	if _, ok := cells.Get(addr); ok {
+		t.Error("expected key to be deleted")
+	}
+
+	if cells.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", cells.Len())
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestMapType_ZeroAllocLookup(t *testing.T) {
+	var cells lattice.Map[float64]
+
+	for i := range 100 {
+		cells.Set(lattice.New(i, i+1, i+2), float64(i))
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = cells.Get(lattice.New(7, 8, 9))
+		_ = cells.GetOrZero(lattice.New(70, 71, 72))
+		cells.Set(lattice.New(1, 2, 3), 1)
+	})
+	if allocs != 0 {
+		t.Errorf("lookup/overwrite allocs = %v, want 0", allocs)
+	}
+}
+
+func BenchmarkMapType_Lookup_3D(b *testing.B) {
+	var cells lattice.Map[float64]
+	for i := range 10000 {
+		cells.Set(lattice.New(i, i+1, i+2), float64(i))
+	}
+
+	for i := 0; b.Loop(); i++ {
+		idx := i % 10000
+		_, _ = cells.Get(lattice.New(idx, idx+1, idx+2))
+	}
+}
+
+func TestMapType_Clone(t *testing.T) {
+	t.Parallel()
+
+	var orig lattice.Map[[]int]
+
+	orig.Set(lattice.New(1), []int{1})
+	orig.Set(lattice.New(2), []int{2})
+
+	clone := orig.Clone()
+	clone.Set(lattice.New(3), []int{3})
+	clone.Delete(lattice.New(1))
+
+	if orig.Len() != 2 || orig.GetOrZero(lattice.New(1)) == nil {
+		t.Errorf("mutating the clone changed the original: Len() = %d", orig.Len())
+	}
+
+	if _, ok := orig.Get(lattice.New(3)); ok {
+		t.Error("original gained the clone's new entry")
+	}
+
+	// The copy is shallow: both maps share the slice stored at New(2).
+	clone.GetOrZero(lattice.New(2))[0] = 99
+	if got := orig.GetOrZero(lattice.New(2))[0]; got != 99 {
+		t.Errorf("shared value = %d, want 99", got)
+	}
+
+	var empty lattice.Map[int]
+
+	if c := empty.Clone(); c.Len() != 0 {
+		t.Errorf("Clone of zero Map has Len() = %d", c.Len())
+	}
+}
+
+func TestMapType_Equal(t *testing.T) {
+	t.Parallel()
+
+	eq := func(a, b float64) bool { return a == b }
+
+	var left, right, empty lattice.Map[float64]
+
+	left.Set(lattice.New(1, 2), 1.5)
+	left.Set(lattice.New(3, 4), 2.5)
+
+	if !left.Equal(left.Clone(), eq) {
+		t.Error("Map is not Equal to its clone")
+	}
+
+	right.Set(lattice.New(1, 2), 1.5)
+
+	if left.Equal(&right, eq) || right.Equal(&left, eq) {
+		t.Error("maps with different keys are Equal")
+	}
+
+	right.Set(lattice.New(3, 4), 2.6)
+
+	if left.Equal(&right, eq) {
+		t.Error("maps with different values are Equal")
+	}
+
+	near := func(a, b float64) bool { return math.Abs(a-b) < 0.2 }
+	if !left.Equal(&right, near) {
+		t.Error("Equal ignored the caller's equality function")
+	}
+
+	if !empty.Equal(&lattice.Map[float64]{}, eq) {
+		t.Error("empty maps are not Equal")
+	}
+}
+
+// ============================================================
+// SumMap
+// ============================================================
+
+func TestSumMap_Accumulate(t *testing.T) {
+	t.Parallel()
+
+	var counts lattice.SumMap[int]
+
+	addr1 := lattice.New(1, 2)
+	addr2 := lattice.New(3, 4)
+
+	counts.Add(addr1, 5) // missing key starts at delta
+	counts.Add(addr1, -2)
+	counts.Inc(addr1)
+	counts.Inc(addr2)
+
+	if got := counts.Get(addr1); got != 4 {
+		t.Errorf("Get(addr1) = %d, want 4", got)
+	}
+
+	if got := counts.Get(addr2); got != 1 {
+		t.Errorf("Get(addr2) = %d, want 1", got)
+	}
+
+	if got := counts.Get(lattice.New(9, 9)); got != 0 {
+		t.Errorf("Get(missing) = %d, want 0", got)
+	}
+
+	if counts.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", counts.Len())
+	}
+}
+
+func TestSumMap_Overwrite(t *testing.T) {
+	t.Parallel()
+
+	var sums lattice.SumMap[float64]
+
+	addr := lattice.New(1, 2, 3)
+	sums.Add(addr, 1.5)
+	sums.Set(addr, 10)
+	sums.Add(addr, 0.25)
+
+	if got := sums.Get(addr); got != 10.25 {
+		t.Errorf("Get() = %v, want 10.25", got)
+	}
+}
+
+func TestSumMap_Total(t *testing.T) {
+	t.Parallel()
+
+	var (
+		empty lattice.SumMap[uint32]
+		sums  lattice.SumMap[uint32]
+	)
+
+	if got := empty.Total(); got != 0 {
+		t.Errorf("empty Total() = %d, want 0", got)
+	}
+
+	want := uint32(0)
+
+	for i := range 100 {
+		sums.Add(lattice.New(i%10, i/10), uint32(i))      //nolint:gosec // i < 100
+		sums.Add(lattice.New(i%10, i/10, 1), uint32(i*2)) //nolint:gosec // i < 100
+
+		want += uint32(i * 3) //nolint:gosec // i < 100
+	}
+
+	if got := sums.Total(); got != want {
+		t.Errorf("Total() = %d, want %d", got, want)
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestSumMap_ZeroAllocs(t *testing.T) {
+	var sums lattice.SumMap[float64]
+
+	for i := range 1000 {
+		sums.Inc(lattice.New(i, i))
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		sums.Add(lattice.New(5, 5), 1)
+		_ = sums.Total()
+	})
+	if allocs != 0 {
+		t.Errorf("Add/Total allocs = %v, want 0", allocs)
+	}
+}
+
+// ============================================================
+// NewMap / Fill
+// ============================================================
+
+func TestFill(t *testing.T) {
+	t.Parallel()
+
+	addrs := []lattice.Addr{lattice.New(1, 2), lattice.New(3, 4), lattice.New(1, 2), lattice.New(5)}
+	values := []string{"a", "b", "c", "d"}
+
+	m := lattice.NewMap[string](len(addrs))
+	m[lattice.New(9, 9)] = "kept"
+
+	lattice.Fill(m, addrs, values)
+
+	want := map[lattice.Addr]string{lattice.New(1, 2): "c", lattice.New(3, 4): "b", lattice.New(5): "d", lattice.New(9, 9): "kept"}
+	if !maps.Equal(m, want) {
+		t.Errorf("Fill = %v, want %v", m, want)
+	}
+
+	lattice.Fill(m, nil, nil)
+
+	if len(m) != len(want) {
+		t.Errorf("empty Fill changed the map: %v", m)
+	}
+}
+
+func TestFill_PanicLengthMismatch(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if got, want := fmt.Sprintf("%v", recover()), "lattice: Fill got 2 addresses and 1 values"; got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	lattice.Fill(lattice.NewMap[int](2), []lattice.Addr{lattice.New(1), lattice.New(2)}, []int{1})
+}
+
+// ============================================================
+// Entries
+// ============================================================
+
+func TestEntries(t *testing.T) {
+	t.Parallel()
+
+	m := testCube()
+	seen := make(map[lattice.Addr]int, len(m))
+
+	for e := range lattice.Entries(m) {
+		if _, dup := seen[e.Addr]; dup {
+			t.Fatalf("entry %v yielded twice", e.Addr)
+		}
+
+		seen[e.Addr] = e.Value
+	}
+
+	if !maps.Equal(seen, m) {
+		t.Errorf("Entries collected %d entries that differ from the map's %d", len(seen), len(m))
+	}
+}
+
+func TestEntries_EarlyStop(t *testing.T) {
+	t.Parallel()
+
+	n := 0
+	for range lattice.Entries(testCube()) {
+		n++
+		if n == 3 {
+			break
+		}
+	}
+
+	if n != 3 {
+		t.Errorf("stopped after %d entries, want 3", n)
+	}
+
+	for range lattice.Entries(map[lattice.Addr]string(nil)) {
+		t.Error("nil map yielded an entry")
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestEntries_ZeroAllocsPerEntry(t *testing.T) {
+	m := testCube()
+	small := map[lattice.Addr]int{lattice.New(1): 1}
+
+	count := func(m map[lattice.Addr]int) float64 {
+		return testing.AllocsPerRun(10, func() {
+			for e := range lattice.Entries(m) {
+				_ = e
+			}
+		})
+	}
+
+	if big, one := count(m), count(small); big != one {
+		t.Errorf("allocs over %d entries = %v, over 1 entry = %v; want equal", len(m), big, one)
+	}
+}
+
+// ============================================================
+// SortedEntries
+// ============================================================
+
+func TestSortedEntries_Order(t *testing.T) {
+	t.Parallel()
+
+	m := testCube()
+	m[lattice.New(3, 3)] = -1
+	m[lattice.New(1)] = -2
+
+	var prev lattice.Addr
+
+	n := 0
+	for a, v := range lattice.SortedEntries(m) {
+		if n > 0 && prev.Compare(a) >= 0 {
+			t.Fatalf("key %v yielded after %v", a, prev)
+		}
+
+		if v != m[a] {
+			t.Errorf("value at %v = %d, want %d", a, v, m[a])
+		}
+
+		prev = a
+		n++
+	}
+
+	if n != len(m) {
+		t.Errorf("yielded %d entries, want %d", n, len(m))
+	}
+}
+
+func TestSortedEntries_MortonOrder(t *testing.T) {
+	t.Parallel()
+
+	m := map[lattice.Addr]string{lattice.New(1, 1): "d", lattice.New(0, 1): "c", lattice.New(1, 0): "b", lattice.New(0, 0): "a", lattice.New(2, 0): "e"}
+
+	var got []string
+	for _, v := range lattice.SortedEntries(m) {
+		got = append(got, v)
+	}
+
+	if want := []string{"a", "b", "c", "d", "e"}; !slices.Equal(got, want) {
+		t.Errorf("SortedEntries values = %v, want %v", got, want)
+	}
+}
+
+func TestSortedEntries_DeleteDuringIteration(t *testing.T) {
+	t.Parallel()
+
+	m := map[lattice.Addr]int{lattice.New(0): 0, lattice.New(1): 1, lattice.New(2): 2, lattice.New(3): 3}
+
+	var got []int
+
+	for a, v := range lattice.SortedEntries(m) {
+		got = append(got, v)
+
+		next, _ := a.Next()
+		delete(m, next)
+	}
+
+	if want := []int{0, 2}; !slices.Equal(got, want) {
+		t.Errorf("SortedEntries values = %v, want %v", got, want)
+	}
+}
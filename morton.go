@@ -0,0 +1,211 @@
+package lattice
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+const (
+	// chunkBits is the number of coordinate bits spread or gathered per table lookup.
+	chunkBits = 4
+
+	// chunkMask selects one chunk of a coordinate.
+	chunkMask = 1<<chunkBits - 1
+
+	// chunksPerCoord is the number of chunks making up one coordinate.
+	chunksPerCoord = BitsPerCoord / chunkBits
+
+	// zIndexMaxDims is the largest dimension count whose interleaved bits
+	// fit in a uint64: 3 dims × 20 bits = 60 bits.
+	zIndexMaxDims = bitsPerWord / BitsPerCoord
+)
+
+// spreadTable[dims][nibble] holds nibble with its bits moved dims positions
+// apart, i.e. bit k of nibble lands at bit k*dims. Because the stride is
+// the dimension count, one entry places a whole chunk of a coordinate into
+// the interleaved layout.
+var spreadTable = buildSpreadTable()
+
+// buildSpreadTable precomputes spreadTable for every dimension count.
+func buildSpreadTable() [MaxDimensions + 1][chunkMask + 1]uint64 {
+	var table [MaxDimensions + 1][chunkMask + 1]uint64
+
+	for dims := 1; dims <= MaxDimensions; dims++ {
+		for nibble := range chunkMask + 1 {
+			for bit := range chunkBits {
+				if nibble>>bit&1 == 1 {
+					table[dims][nibble] |= 1 << (bit * dims)
+				}
+			}
+		}
+	}
+
+	return table
+}
+
+// interleave encodes coords one chunk at a time using spreadTable.
+func interleave(coords []int) Addr {
+	var addr Addr
+
+	numDims := len(coords)
+	addr[0] = uint64(numDims)
+
+	if numDims == 0 {
+		return addr
+	}
+
+	spread := &spreadTable[numDims]
+	rowStride := chunkBits * numDims
+
+	for dimIdx, coord := range coords {
+		for chunk := range chunksPerCoord {
+			nibble := coord >> (chunk * chunkBits) & chunkMask
+			if nibble != 0 {
+				addr.orBits(dimsBits+chunk*rowStride+dimIdx, spread[nibble])
+			}
+		}
+	}
+
+	return addr
+}
+
+// deinterleave decodes a one chunk at a time. Each chunk of the
+// interleaved bits holds one nibble of every coordinate; a nibble is
+// selected with the spreadTable mask and folded back into 4 adjacent bits.
+func deinterleave(a Addr) (Buffer, int) {
+	var coords Buffer
+
+	dims := a.Dims()
+	if dims == 0 {
+		return coords, 0
+	}
+
+	mask := spreadTable[dims][chunkMask]
+	rowStride := chunkBits * dims
+
+	for chunk := range chunksPerCoord {
+		window := a.bitsAt(dimsBits+chunk*rowStride, rowStride)
+
+		for dimIdx := range dims {
+			coords[dimIdx] |= gatherNibble(window>>dimIdx&mask, dims) << (chunk * chunkBits)
+		}
+	}
+
+	return coords, dims
+}
+
+// gatherNibble packs the bits at positions 0, dims, 2*dims and 3*dims of
+// sparse into the low 4 bits.
+func gatherNibble(sparse uint64, dims int) int {
+	if dims == 1 {
+		return int(sparse) //nolint:gosec // masked to chunkBits bits
+	}
+
+	// Pair up bits 0,dims into 0,1 and bits 2*dims,3*dims into 2*dims,2*dims+1.
+	pairs := (sparse | sparse>>(dims-1)) & (0b11 | 0b11<<(2*dims))
+
+	// Bring the upper pair down next to the lower one.
+	return int((pairs | pairs>>(2*dims-2)) & chunkMask) //nolint:gosec // masked to chunkBits bits
+}
+
+// ZIndex returns the raw Morton code of the address: its interleaved
+// coordinate bits without the dimension header, as used by other Morton
+// libraries. Bit k*Dims()+i holds bit k of coordinate i.
+//
+// The code fits in a uint64 only when Dims()*20 <= 64, i.e. for up to 3
+// dimensions (60 bits); ok is false for 4 or more dimensions.
+func (a Addr) ZIndex() (uint64, bool) {
+	dims := a.Dims()
+	if dims > zIndexMaxDims {
+		return 0, false
+	}
+
+	return a.bitsAt(dimsBits, dims*BitsPerCoord), true
+}
+
+// FromZIndex reconstructs an address from a raw Morton code produced by
+// ZIndex. Returns an error wrapping ErrTooManyDims if dims is outside
+// [0, 3], or ErrCoordRange if z has bits set beyond dims*20.
+func FromZIndex(dims int, z uint64) (Addr, error) {
+	if dims < 0 || dims > zIndexMaxDims {
+		return Addr{}, fmt.Errorf("%w: z-index supports 0 to %d dimensions, got %d", ErrTooManyDims, zIndexMaxDims, dims)
+	}
+
+	if z>>(dims*BitsPerCoord) != 0 {
+		return Addr{}, fmt.Errorf("%w: z-index %#x exceeds %d bits", ErrCoordRange, z, dims*BitsPerCoord)
+	}
+
+	var addr Addr
+
+	addr[0] = uint64(dims) //nolint:gosec // dims in [0, zIndexMaxDims]
+	addr.orBits(dimsBits, z)
+
+	return addr, nil
+}
+
+// zStep is one step along the curve as a raw 256-bit value: the lowest
+// interleaved coordinate bit, just above the dimension header.
+var zStep = Addr{1 << dimsBits}
+
+// Next returns the address whose interleaved coordinate bits are one
+// greater than a's, with the same Dims(): the successor along the Z-curve.
+// A single step may change several coordinates at once, e.g. (1,1) is
+// followed by (2,0) in 2D. ok is false if a is the last encodable address
+// for its dimensionality.
+//
+// Encoding flags are preserved, so on a Hilbert-encoded address Next steps
+// along the Hilbert curve instead.
+func (a Addr) Next() (Addr, bool) {
+	flags := a[flagWord] & flagsMask
+	a[flagWord] &^= flagsMask
+
+	next, carry := add256(a, zStep)
+	if carry || next.strayWord() >= 0 {
+		return Addr{}, false
+	}
+
+	next[flagWord] |= flags
+
+	return next, true
+}
+
+// Prev returns the address whose interleaved coordinate bits are one less
+// than a's, with the same Dims(): the predecessor along the Z-curve.
+// ok is false if every coordinate of a is zero. Like Next, Prev preserves
+// encoding flags.
+func (a Addr) Prev() (Addr, bool) {
+	flags := a[flagWord] & flagsMask
+	a[flagWord] &^= flagsMask
+
+	if a == (Addr{a[0] & dimsMask}) {
+		return Addr{}, false
+	}
+
+	prev := sub256(a, zStep)
+	prev[flagWord] |= flags
+
+	return prev, true
+}
+
+// BitDistance returns the Hamming distance between the interleaved
+// coordinate bits of a and b: the number of bit positions at which they
+// differ. The dimension header and encoding flags are excluded, and
+// addresses of different dimensionality are compared bit for bit without
+// error.
+//
+// BitDistance measures encoded bits, not coordinate differences: New(7)
+// and New(8) are adjacent cells yet differ in 4 bits. It is cheap enough
+// for locality-sensitive bucketing. Zero allocations.
+func (a Addr) BitDistance(b Addr) int {
+	a[0] &^= dimsMask
+	b[0] &^= dimsMask
+	a[flagWord] &^= flagsMask
+	b[flagWord] &^= flagsMask
+
+	n := 0
+	for i := range a {
+		n += bits.OnesCount64(a[i] ^ b[i])
+	}
+
+	return n
+}
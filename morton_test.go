@@ -0,0 +1,372 @@
+package lattice
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// ============================================================
+// Reference encoder
+// ============================================================
+
+// encodeBitwise interleaves coords one bit at a time. It is the reference
+// layout every optimized encoder must match bit for bit.
+func encodeBitwise(coords []int) Addr {
+	var addr Addr
+
+	addr[0] = uint64(len(coords))
+
+	numDims := len(coords)
+	for bitPos := range BitsPerCoord {
+		for dimIdx := range numDims {
+			if (coords[dimIdx]>>bitPos)&1 == 1 {
+				encodedBitPos := dimsBits + bitPos*numDims + dimIdx
+				addr[encodedBitPos/bitsPerWord] |= 1 << (encodedBitPos % bitsPerWord)
+			}
+		}
+	}
+
+	return addr
+}
+
+// decodeBitwise is the bit-at-a-time inverse of encodeBitwise.
+func decodeBitwise(a Addr) (Buffer, int) {
+	var coords Buffer
+
+	dims := a.Dims()
+
+	for bitPos := range BitsPerCoord {
+		for dimIdx := range dims {
+			encodedBitPos := dimsBits + bitPos*dims + dimIdx
+			if (a[encodedBitPos/bitsPerWord]>>(encodedBitPos%bitsPerWord))&1 == 1 {
+				coords[dimIdx] |= 1 << bitPos
+			}
+		}
+	}
+
+	return coords, dims
+}
+
+// randomCoordSets returns n coordinate sets for every dimension count,
+// starting with bit-pattern edge cases followed by random values.
+func randomCoordSets(n int) [][]int {
+	rng := rand.New(rand.NewPCG(1, 2)) //nolint:gosec // deterministic test data
+	edges := []int{0, 1, 0x55555, 0xAAAAA, MaxCoordValue - 1, MaxCoordValue}
+	sets := make([][]int, 0, n*(MaxDimensions+1))
+
+	for dims := 0; dims <= MaxDimensions; dims++ {
+		for iter := range n {
+			coords := make([]int, dims)
+
+			for i := range coords {
+				if iter < len(edges) {
+					coords[i] = edges[(iter+i)%len(edges)]
+				} else {
+					coords[i] = rng.IntN(MaxCoordValue + 1)
+				}
+			}
+
+			sets = append(sets, coords)
+		}
+	}
+
+	return sets
+}
+
+// ============================================================
+// Table-driven encoder parity
+// ============================================================
+
+func TestInterleave_MatchesBitwise(t *testing.T) {
+	t.Parallel()
+
+	for _, coords := range randomCoordSets(2000) {
+		want := encodeBitwise(coords)
+
+		got := interleave(coords)
+		if got != want {
+			t.Fatalf("interleave(%v) = %x, want %x", coords, got, want)
+		}
+
+		gotCoords, gotDims := deinterleave(want)
+		wantCoords, wantDims := decodeBitwise(want)
+
+		if gotCoords != wantCoords || gotDims != wantDims {
+			t.Fatalf("deinterleave(%x) = %v/%d, want %v/%d", want, gotCoords, gotDims, wantCoords, wantDims)
+		}
+	}
+}
+
+// ============================================================
+// Benchmarks
+// ============================================================
+
+func BenchmarkNew_3D_Bitwise(b *testing.B) {
+	coords := []int{100, 200, 300}
+
+	for b.Loop() {
+		_ = encodeBitwise(coords)
+	}
+}
+
+func BenchmarkNew_12D_Bitwise(b *testing.B) {
+	coords := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+
+	for b.Loop() {
+		_ = encodeBitwise(coords)
+	}
+}
+
+func BenchmarkCoords_12D_Bitwise(b *testing.B) {
+	addr := New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12)
+
+	for b.Loop() {
+		_, _ = decodeBitwise(addr)
+	}
+}
+
+// ============================================================
+// ZIndex / FromZIndex
+// ============================================================
+
+func TestZIndex(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		addr Addr
+		want uint64
+	}{
+		{"empty", New(), 0},
+		{"1D", New(MaxCoordValue), MaxCoordValue},
+		{"2D x", New(1, 0), 0b01},
+		{"2D y", New(0, 1), 0b10},
+		{"2D", New(3, 5), 0b100111},
+		{"3D", New(1, 2, 4), 0b100_010_001},
+		{"3D max", New(MaxCoordValue, MaxCoordValue, MaxCoordValue), 1<<60 - 1},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			z, ok := testCase.addr.ZIndex()
+			if !ok || z != testCase.want {
+				t.Fatalf("ZIndex() = %#x, %v, want %#x, true", z, ok, testCase.want)
+			}
+
+			got, err := FromZIndex(testCase.addr.Dims(), z)
+			if err != nil {
+				t.Fatalf("FromZIndex() error = %v", err)
+			}
+
+			if !got.Equal(testCase.addr) {
+				t.Errorf("FromZIndex() = %v, want %v", got, testCase.addr)
+			}
+		})
+	}
+}
+
+func TestZIndex_64BitBoundary(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := New(1, 2, 3, 4).ZIndex(); ok {
+		t.Error("ZIndex() ok = true for 4 dimensions (80 bits), want false")
+	}
+
+	tests := []struct {
+		name    string
+		dims    int
+		z       uint64
+		wantErr error
+	}{
+		{"negative dims", -1, 0, ErrTooManyDims},
+		{"4 dims", 4, 0, ErrTooManyDims},
+		{"0 dims with bits", 0, 1, ErrCoordRange},
+		{"1D bit 20", 1, 1 << 20, ErrCoordRange},
+		{"2D bit 40", 2, 1 << 40, ErrCoordRange},
+		{"3D bit 60", 3, 1 << 60, ErrCoordRange},
+		{"3D all 64 bits", 3, ^uint64(0), ErrCoordRange},
+		{"3D top valid bit", 3, 1 << 59, nil},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := FromZIndex(testCase.dims, testCase.z); !errors.Is(err, testCase.wantErr) {
+				t.Errorf("FromZIndex(%d, %#x) error = %v, want %v", testCase.dims, testCase.z, err, testCase.wantErr)
+			}
+		})
+	}
+}
+
+// ============================================================
+// Next / Prev
+// ============================================================
+
+func TestNext_ZOrder(t *testing.T) {
+	t.Parallel()
+
+	want := []Addr{New(0, 0), New(1, 0), New(0, 1), New(1, 1), New(2, 0), New(3, 0), New(2, 1)}
+
+	a := want[0]
+	for i, w := range want[1:] {
+		next, ok := a.Next()
+		if !ok || next != w {
+			t.Fatalf("step %d: %v.Next() = %v, %v; want %v, true", i+1, a, next, ok, w)
+		}
+
+		prev, ok := next.Prev()
+		if !ok || prev != a {
+			t.Fatalf("%v.Prev() = %v, %v; want %v, true", next, prev, ok, a)
+		}
+
+		a = next
+	}
+}
+
+func TestNext_MatchesSortedBox(t *testing.T) {
+	t.Parallel()
+
+	var cells []Addr
+	for a := range Box(New(0, 0, 0), New(7, 7, 7)) {
+		cells = append(cells, a)
+	}
+
+	slices.SortFunc(cells, Addr.Compare)
+
+	for i := 1; i < len(cells); i++ {
+		if next, ok := cells[i-1].Next(); !ok || next != cells[i] {
+			t.Fatalf("%v.Next() = %v, %v; want %v", cells[i-1], next, ok, cells[i])
+		}
+	}
+}
+
+func TestNextPrev_Identity(t *testing.T) {
+	t.Parallel()
+
+	for _, a := range []Addr{
+		New(1),
+		New(5, 9),
+		New(MaxCoordValue, 0, 17),
+		New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12),
+		NewSigned(-3, 4),
+	} {
+		next, ok := a.Next()
+		if !ok {
+			t.Fatalf("%v.Next() not ok", a)
+		}
+
+		if back, ok := next.Prev(); !ok || back != a {
+			t.Errorf("%v.Next().Prev() = %v, %v", a, back, ok)
+		}
+
+		prev, ok := a.Prev()
+		if !ok {
+			t.Fatalf("%v.Prev() not ok", a)
+		}
+
+		if back, ok := prev.Next(); !ok || back != a {
+			t.Errorf("%v.Prev().Next() = %v, %v", a, back, ok)
+		}
+	}
+}
+
+func TestNextPrev_Boundaries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		a    Addr
+		next bool
+		prev bool
+	}{
+		{"empty", New(), false, false},
+		{"1D origin", New(0), true, false},
+		{"1D max", New(MaxCoordValue), false, true},
+		{"3D origin", New(0, 0, 0), true, false},
+		{"3D max", New(MaxCoordValue, MaxCoordValue, MaxCoordValue), false, true},
+		{"12D max", New(slices.Repeat([]int{MaxCoordValue}, MaxDimensions)...), false, true},
+		{"signed origin", NewSigned(MinSignedCoord, MinSignedCoord), true, false},
+		{"Hilbert interior", NewHilbert(5, 9), true, true},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, ok := testCase.a.Next(); ok != testCase.next {
+				t.Errorf("%v.Next() ok = %v, want %v", testCase.a, ok, testCase.next)
+			}
+
+			if _, ok := testCase.a.Prev(); ok != testCase.prev {
+				t.Errorf("%v.Prev() ok = %v, want %v", testCase.a, ok, testCase.prev)
+			}
+		})
+	}
+}
+
+func TestNext_Hilbert(t *testing.T) {
+	t.Parallel()
+
+	a := NewHilbert(0, 0)
+
+	for range 64 {
+		next, ok := a.Next()
+		if !ok || !next.IsHilbert() {
+			t.Fatalf("%v.Next() = %v, %v", a, next, ok)
+		}
+
+		prev, dims := a.HilbertCoords()
+		cur, _ := next.HilbertCoords()
+
+		if d := New(prev[:dims]...).ManhattanDistance(New(cur[:dims]...)); d != 1 {
+			t.Fatalf("Hilbert step from %v to %v jumps %d", prev[:dims], cur[:dims], d)
+		}
+
+		a = next
+	}
+}
+
+// ============================================================
+// BitDistance
+// ============================================================
+
+func TestBitDistance(t *testing.T) {
+	t.Parallel()
+
+	flagged := New(2, 3)
+	flagged[flagWord] |= flagsMask
+
+	tests := []struct {
+		name string
+		a, b Addr
+		want int
+	}{
+		{"identical", New(3, 5, 7), New(3, 5, 7), 0},
+		{"empty", New(), New(), 0},
+		{"single bit", New(4, 5), New(4, 4), 1},
+		{"high bit", New(0, 0, 0), New(0, 0, 1<<19), 1},
+		{"carry", New(7), New(8), 4},
+		{"all bits", New(0, 0), New(MaxCoordValue, MaxCoordValue), 2 * BitsPerCoord},
+		{"header ignored", New(1), New(1, 0), 0},
+		{"flags ignored", New(2, 3), flagged, 0},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := testCase.a.BitDistance(testCase.b); got != testCase.want {
+				t.Errorf("%v.BitDistance(%v) = %d, want %d", testCase.a, testCase.b, got, testCase.want)
+			}
+
+			if got := testCase.b.BitDistance(testCase.a); got != testCase.want {
+				t.Errorf("BitDistance not symmetric: %d vs %d", got, testCase.want)
+			}
+		})
+	}
+}
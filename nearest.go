@@ -0,0 +1,141 @@
+package lattice
+
+// Nearest returns the key of m closest to q by ManhattanDistance, together
+// with its value. Keys whose Dims() differ from q and Hilbert-encoded keys
+// are skipped; ok is false if no key qualifies. Ties resolve to the
+// smallest key under Compare, so the result does not depend on map
+// iteration order.
+//
+// Nearest is a linear scan: O(len(m)) time, zero allocations.
+func Nearest[V any](m map[Addr]V, q Addr) (Addr, V, bool) {
+	var (
+		best      Addr
+		bestValue V
+	)
+
+	bestDist := -1
+
+	q.mustMorton()
+
+	for a, v := range m {
+		d, err := q.TryManhattanDistance(a)
+		if err != nil {
+			continue
+		}
+
+		if bestDist < 0 || d < bestDist || (d == bestDist && a.Compare(best) < 0) {
+			best, bestValue, bestDist = a, v, d
+		}
+	}
+
+	return best, bestValue, bestDist >= 0
+}
+
+// KNearest returns up to k keys of m closest to q by ManhattanDistance,
+// sorted by ascending distance. Keys whose Dims() differ from q and
+// Hilbert-encoded keys are skipped, and ties are ordered by Compare,
+// exactly as for Nearest, so the result is deterministic. Returns nil if
+// k <= 0 or no key qualifies.
+//
+// KNearest keeps a bounded max-heap of the k best candidates seen so far,
+// so it uses O(k) memory and O(len(m) log k) time.
+func KNearest[V any](m map[Addr]V, q Addr, k int) []Entry[V] {
+	if k <= 0 {
+		return nil
+	}
+
+	q.mustMorton()
+
+	h := knnHeap[V]{items: make([]knnItem[V], 0, min(k, len(m)))}
+
+	for a, v := range m {
+		d, err := q.TryManhattanDistance(a)
+		if err != nil {
+			continue
+		}
+
+		item := knnItem[V]{Entry: Entry[V]{Addr: a, Value: v}, dist: d}
+
+		switch {
+		case len(h.items) < k:
+			h.push(item)
+		case h.items[0].worse(item):
+			h.items[0] = item
+			h.down(0, len(h.items))
+		}
+	}
+
+	if len(h.items) == 0 {
+		return nil
+	}
+
+	// Heapsort in place: repeatedly move the worst item to the end.
+	for n := len(h.items) - 1; n > 0; n-- {
+		h.items[0], h.items[n] = h.items[n], h.items[0]
+		h.down(0, n)
+	}
+
+	out := make([]Entry[V], len(h.items))
+	for i, item := range h.items {
+		out[i] = item.Entry
+	}
+
+	return out
+}
+
+// knnItem is a KNearest candidate with its distance from the query.
+type knnItem[V any] struct {
+	Entry[V]
+
+	dist int
+}
+
+// worse reports whether x ranks after y: farther away, or equally far and
+// larger under Compare.
+func (x knnItem[V]) worse(y knnItem[V]) bool {
+	if x.dist != y.dist {
+		return x.dist > y.dist
+	}
+
+	return x.Addr.Compare(y.Addr) > 0
+}
+
+// knnHeap is a max-heap of candidates keyed by worse, with the worst
+// candidate at the root.
+type knnHeap[V any] struct {
+	items []knnItem[V]
+}
+
+func (h *knnHeap[V]) push(item knnItem[V]) {
+	h.items = append(h.items, item)
+
+	for i := len(h.items) - 1; i > 0; {
+		parent := (i - 1) / 2
+		if !h.items[i].worse(h.items[parent]) {
+			break
+		}
+
+		h.items[i], h.items[parent] = h.items[parent], h.items[i]
+		i = parent
+	}
+}
+
+// down restores the heap property below i within items[:n].
+func (h *knnHeap[V]) down(i, n int) {
+	for {
+		worst := i
+
+		for _, child := range [2]int{2*i + 1, 2*i + 2} {
+			if child < n && h.items[child].worse(h.items[worst]) {
+				worst = child
+			}
+		}
+
+		if worst == i {
+			return
+		}
+
+		h.items[i], h.items[worst] = h.items[worst], h.items[i]
+		i = worst
+	}
+}
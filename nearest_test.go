@@ -0,0 +1,183 @@
+package lattice_test
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"github.com/aclivo/lattice"
+)
+
+// ============================================================
+// Nearest
+// ============================================================
+
+func TestNearest_Unique(t *testing.T) {
+	t.Parallel()
+
+	m := map[lattice.Addr]string{
+		lattice.New(0, 0):   "origin",
+		lattice.New(10, 10): "far",
+		lattice.New(4, 6):   "near",
+		lattice.New(5):      "wrong dims",
+	}
+
+	a, v, ok := lattice.Nearest(m, lattice.New(5, 5))
+	if !ok || a != lattice.New(4, 6) || v != "near" {
+		t.Errorf("Nearest = %v, %q, %v; want Addr[4 6], \"near\", true", a, v, ok)
+	}
+}
+
+func TestNearest_Exact(t *testing.T) {
+	t.Parallel()
+
+	m := map[lattice.Addr]int{lattice.New(1, 2, 3): 7, lattice.New(1, 2, 4): 8}
+
+	a, v, ok := lattice.Nearest(m, lattice.New(1, 2, 3))
+	if !ok || a != lattice.New(1, 2, 3) || v != 7 {
+		t.Errorf("Nearest = %v, %d, %v; want Addr[1 2 3], 7, true", a, v, ok)
+	}
+}
+
+func TestNearest_TieBreaksByCompare(t *testing.T) {
+	t.Parallel()
+
+	// All four keys are at distance 2 from (5,5).
+	keys := []lattice.Addr{lattice.New(7, 5), lattice.New(5, 7), lattice.New(3, 5), lattice.New(5, 3)}
+	m := make(map[lattice.Addr]int, len(keys))
+
+	want := keys[0]
+	for i, k := range keys {
+		m[k] = i
+
+		if k.Compare(want) < 0 {
+			want = k
+		}
+	}
+
+	for range 20 {
+		if a, _, _ := lattice.Nearest(m, lattice.New(5, 5)); a != want {
+			t.Fatalf("Nearest = %v, want %v", a, want)
+		}
+	}
+}
+
+func TestNearest_NoCandidates(t *testing.T) {
+	t.Parallel()
+
+	if _, _, ok := lattice.Nearest(map[lattice.Addr]int(nil), lattice.New(1, 2)); ok {
+		t.Error("Nearest on nil map: ok = true")
+	}
+
+	if _, _, ok := lattice.Nearest(map[lattice.Addr]int{lattice.New(1): 1, lattice.New(1, 2, 3): 2}, lattice.New(1, 2)); ok {
+		t.Error("Nearest with no same-dims keys: ok = true")
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestNearest_ZeroAllocs(t *testing.T) {
+	m := testCube()
+	q := lattice.New(4, 5, 6)
+
+	if allocs := testing.AllocsPerRun(10, func() { lattice.Nearest(m, q) }); allocs != 0 {
+		t.Errorf("Nearest allocs = %v, want 0", allocs)
+	}
+}
+
+// ============================================================
+// KNearest
+// ============================================================
+
+func TestKNearest_Sorted(t *testing.T) {
+	t.Parallel()
+
+	m := testCube()
+	q := lattice.New(4, 5, 6)
+
+	got := lattice.KNearest(m, q, 7)
+	if len(got) != 7 {
+		t.Fatalf("KNearest returned %d results, want 7", len(got))
+	}
+
+	if got[0].Addr != q || got[0].Value != 456 {
+		t.Errorf("KNearest[0] = %v, want the query cell with value 456", got[0])
+	}
+
+	for i, r := range got[1:] {
+		if d := q.ManhattanDistance(r.Addr); d != 1 {
+			t.Errorf("KNearest[%d] = %v at distance %d, want 1", i+1, r.Addr, d)
+		}
+
+		if r.Value != m[r.Addr] {
+			t.Errorf("KNearest[%d] value = %d, want %d", i+1, r.Value, m[r.Addr])
+		}
+
+		if i > 0 && got[i].Addr.Compare(r.Addr) >= 0 {
+			t.Errorf("KNearest ties out of order: %v before %v", got[i].Addr, r.Addr)
+		}
+	}
+}
+
+func TestKNearest_MatchesFullSort(t *testing.T) {
+	t.Parallel()
+
+	m := make(map[lattice.Addr]int)
+	for i := range 200 {
+		m[lattice.New(i*37%101, i*53%97)] = i
+	}
+
+	q := lattice.New(50, 50)
+
+	want := make([]lattice.Addr, 0, len(m))
+	for a := range m {
+		want = append(want, a)
+	}
+
+	slices.SortFunc(want, func(x, y lattice.Addr) int {
+		return cmp.Or(cmp.Compare(q.ManhattanDistance(x), q.ManhattanDistance(y)), x.Compare(y))
+	})
+
+	for _, k := range []int{1, 5, 32, 199} {
+		got := lattice.KNearest(m, q, k)
+		if len(got) != k {
+			t.Fatalf("k=%d: %d results", k, len(got))
+		}
+
+		for i, r := range got {
+			if r.Addr != want[i] {
+				t.Fatalf("k=%d: result[%d] = %v, want %v", k, i, r.Addr, want[i])
+			}
+		}
+	}
+}
+
+func TestKNearest_KExceedsLen(t *testing.T) {
+	t.Parallel()
+
+	m := map[lattice.Addr]int{lattice.New(0, 0): 0, lattice.New(3, 0): 1, lattice.New(1, 1): 2, lattice.New(2): 3}
+
+	got := lattice.KNearest(m, lattice.New(0, 0), 10)
+	want := []lattice.Entry[int]{{lattice.New(0, 0), 0}, {lattice.New(1, 1), 2}, {lattice.New(3, 0), 1}}
+
+	if !slices.Equal(got, want) {
+		t.Errorf("KNearest = %v, want %v", got, want)
+	}
+}
+
+func TestKNearest_Empty(t *testing.T) {
+	t.Parallel()
+
+	m := map[lattice.Addr]int{lattice.New(1, 2): 1}
+
+	if got := lattice.KNearest(m, lattice.New(1, 2), 0); got != nil {
+		t.Errorf("k=0: got %v, want nil", got)
+	}
+
+	if got := lattice.KNearest(m, lattice.New(1), 3); got != nil {
+		t.Errorf("mismatched dims: got %v, want nil", got)
+	}
+
+	if got := lattice.KNearest(map[lattice.Addr]int(nil), lattice.New(1), 3); got != nil {
+		t.Errorf("nil map: got %v, want nil", got)
+	}
+}
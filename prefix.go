@@ -0,0 +1,165 @@
+package lattice
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// PrefixTree indexes addresses by their coordinate sequence so that every
+// stored address under a prefix can be found without scanning them all.
+// Each level of the tree corresponds to one dimension, below a first level
+// that separates addresses by their encoding flags.
+// The zero value is an empty tree ready to use.
+type PrefixTree[V any] struct {
+	root prefixNode[V]
+	n    int
+}
+
+// prefixNode is one coordinate step in a PrefixTree. Children are kept
+// sorted by coordinate so traversal is deterministic.
+type prefixNode[V any] struct {
+	children []prefixChild[V]
+	key      Addr
+	value    V
+	has      bool
+}
+
+// prefixChild links a coordinate value to the subtree below it.
+type prefixChild[V any] struct {
+	coord int
+	node  *prefixNode[V]
+}
+
+// child returns the subtree for coord, creating it when create is set.
+func (n *prefixNode[V]) child(coord int, create bool) *prefixNode[V] {
+	i, found := n.search(coord)
+	if found {
+		return n.children[i].node
+	}
+
+	if !create {
+		return nil
+	}
+
+	node := &prefixNode[V]{}
+	n.children = slices.Insert(n.children, i, prefixChild[V]{coord: coord, node: node})
+
+	return node
+}
+
+// search returns the position of coord among the children and whether it is present.
+func (n *prefixNode[V]) search(coord int) (int, bool) {
+	return slices.BinarySearchFunc(n.children, coord, func(c prefixChild[V], coord int) int {
+		return cmp.Compare(c.coord, coord)
+	})
+}
+
+// prefixPath returns the keys leading to a: its encoding flags, so that
+// NewSigned(-1) and New(524287) land in separate subtrees, then each
+// coordinate in turn.
+func prefixPath(a Addr) ([MaxDimensions + 1]int, int) {
+	var keys [MaxDimensions + 1]int
+
+	coords, dims := a.Coords()
+	keys[0] = int(a[flagWord] >> (64 - flagBits))
+	copy(keys[1:], coords[:dims])
+
+	return keys, dims + 1
+}
+
+// find returns the node for a, creating the path when create is set.
+func (t *PrefixTree[V]) find(a Addr, create bool) *prefixNode[V] {
+	keys, n := prefixPath(a)
+	node := &t.root
+
+	for i := 0; i < n && node != nil; i++ {
+		node = node.child(keys[i], create)
+	}
+
+	return node
+}
+
+// Insert stores v at a, replacing any previous value.
+func (t *PrefixTree[V]) Insert(a Addr, v V) {
+	node := t.find(a, true)
+	if !node.has {
+		t.n++
+	}
+
+	node.key, node.value, node.has = a, v, true
+}
+
+// Get returns the value stored at a and whether it was present.
+func (t *PrefixTree[V]) Get(a Addr) (V, bool) {
+	if node := t.find(a, false); node != nil && node.has {
+		return node.value, true
+	}
+
+	var zero V
+
+	return zero, false
+}
+
+// Delete removes the value stored at a, if any.
+// Empty branches are pruned so the tree does not grow without bound.
+func (t *PrefixTree[V]) Delete(a Addr) {
+	keys, n := prefixPath(a)
+
+	var nodes [MaxDimensions + 2]*prefixNode[V]
+
+	nodes[0] = &t.root
+
+	for i := range n {
+		if nodes[i+1] = nodes[i].child(keys[i], false); nodes[i+1] == nil {
+			return
+		}
+	}
+
+	node := nodes[n]
+	if !node.has {
+		return
+	}
+
+	var zero V
+
+	node.value, node.has = zero, false
+	t.n--
+
+	for i := n; i > 0 && !nodes[i].has && len(nodes[i].children) == 0; i-- {
+		parent := nodes[i-1]
+		j, _ := parent.search(keys[i-1])
+		parent.children = slices.Delete(parent.children, j, j+1)
+	}
+}
+
+// Len returns the number of stored addresses.
+func (t *PrefixTree[V]) Len() int {
+	return t.n
+}
+
+// Descendants yields every stored address with prefix's encoding that
+// prefix.Contains, including prefix itself, in lexicographic coordinate
+// order.
+func (t *PrefixTree[V]) Descendants(prefix Addr) iter.Seq2[Addr, V] {
+	return func(yield func(Addr, V) bool) {
+		if node := t.find(prefix, false); node != nil {
+			node.walk(yield)
+		}
+	}
+}
+
+// walk yields n and its subtree in pre-order, stopping when yield does.
+func (n *prefixNode[V]) walk(yield func(Addr, V) bool) bool {
+	if n.has && !yield(n.key, n.value) {
+		return false
+	}
+
+	for _, c := range n.children {
+		if !c.node.walk(yield) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,196 @@
+package lattice
+
+import (
+	"slices"
+	"testing"
+)
+
+// testHierarchy returns a region→subregion→cell tree plus its contents.
+func testHierarchy() (*PrefixTree[string], map[Addr]string) {
+	entries := map[Addr]string{
+		New(1):       "region 1",
+		New(1, 1):    "subregion 1.1",
+		New(1, 1, 5): "cell 1.1.5",
+		New(1, 1, 7): "cell 1.1.7",
+		New(1, 2, 3): "cell 1.2.3",
+		New(2, 1, 1): "cell 2.1.1",
+		New(10):      "region 10",
+	}
+
+	var tree PrefixTree[string]
+	for a, v := range entries {
+		tree.Insert(a, v)
+	}
+
+	return &tree, entries
+}
+
+// ============================================================
+// Insert / Get / Delete
+// ============================================================
+
+func TestPrefixTree_InsertGet(t *testing.T) {
+	t.Parallel()
+
+	tree, entries := testHierarchy()
+
+	if tree.Len() != len(entries) {
+		t.Errorf("Len() = %d, want %d", tree.Len(), len(entries))
+	}
+
+	for a, want := range entries {
+		if got, ok := tree.Get(a); !ok || got != want {
+			t.Errorf("Get(%v) = %q, %v, want %q, true", a, got, ok, want)
+		}
+	}
+
+	for _, a := range []Addr{New(), New(2), New(2, 1), New(1, 1, 6), New(3)} {
+		if _, ok := tree.Get(a); ok {
+			t.Errorf("Get(%v) found a value on an interior or missing node", a)
+		}
+	}
+
+	tree.Insert(New(1, 1), "renamed")
+
+	if got, _ := tree.Get(New(1, 1)); got != "renamed" || tree.Len() != len(entries) {
+		t.Errorf("overwrite: Get() = %q, Len() = %d, want %q, %d", got, tree.Len(), "renamed", len(entries))
+	}
+}
+
+func TestPrefixTree_Delete(t *testing.T) {
+	t.Parallel()
+
+	tree, entries := testHierarchy()
+
+	tree.Delete(New(9, 9)) // missing
+	tree.Delete(New(2, 1)) // interior without value
+	tree.Delete(New(1, 1)) // interior with value
+	tree.Delete(New(2, 1, 1))
+
+	if tree.Len() != len(entries)-2 {
+		t.Errorf("Len() = %d, want %d", tree.Len(), len(entries)-2)
+	}
+
+	if _, ok := tree.Get(New(1, 1)); ok {
+		t.Error("Get(Addr[1 1]) found deleted value")
+	}
+
+	if got, ok := tree.Get(New(1, 1, 5)); !ok || got != "cell 1.1.5" {
+		t.Errorf("Delete removed a descendant: Get() = %q, %v", got, ok)
+	}
+
+	if top := tree.find(New(), false); len(top.children) != 2 {
+		t.Errorf("top level has %d children after pruning, want 2", len(top.children))
+	}
+}
+
+func TestPrefixTree_FlagsKeepAddressesApart(t *testing.T) {
+	t.Parallel()
+
+	var tree PrefixTree[string]
+
+	plain, signed := New(524287, 524290), NewSigned(-1, 2)
+	tree.Insert(plain, "plain")
+	tree.Insert(signed, "signed")
+
+	if tree.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", tree.Len())
+	}
+
+	for _, testCase := range []struct {
+		addr Addr
+		want string
+	}{{plain, "plain"}, {signed, "signed"}} {
+		if got, ok := tree.Get(testCase.addr); !ok || got != testCase.want {
+			t.Errorf("Get(%v) = %q, %v, want %q", testCase.addr, got, ok, testCase.want)
+		}
+
+		var got []Addr
+
+		for a := range tree.Descendants(testCase.addr.Truncate(1)) {
+			got = append(got, a)
+		}
+
+		if !slices.Equal(got, []Addr{testCase.addr}) {
+			t.Errorf("Descendants(%v) = %v, want [%v]", testCase.addr.Truncate(1), got, testCase.addr)
+		}
+	}
+
+	tree.Delete(signed)
+
+	if _, ok := tree.Get(plain); !ok || tree.Len() != 1 {
+		t.Errorf("Delete(%v) disturbed %v: Len() = %d", signed, plain, tree.Len())
+	}
+
+	if len(tree.root.children) != 1 {
+		t.Errorf("root has %d flag levels after pruning, want 1", len(tree.root.children))
+	}
+}
+
+// ============================================================
+// Descendants
+// ============================================================
+
+func TestPrefixTree_Descendants(t *testing.T) {
+	t.Parallel()
+
+	tree, entries := testHierarchy()
+
+	tests := []struct {
+		name   string
+		prefix Addr
+		want   []Addr
+	}{
+		{"mid-level", New(1, 1), []Addr{New(1, 1), New(1, 1, 5), New(1, 1, 7)}},
+		{"region", New(1), []Addr{New(1), New(1, 1), New(1, 1, 5), New(1, 1, 7), New(1, 2, 3)}},
+		{"interior without value", New(2), []Addr{New(2, 1, 1)}},
+		{"leaf", New(1, 2, 3), []Addr{New(1, 2, 3)}},
+		{"missing", New(3), nil},
+		{"everything", New(), []Addr{
+			New(1), New(1, 1), New(1, 1, 5), New(1, 1, 7), New(1, 2, 3), New(2, 1, 1), New(10),
+		}},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got []Addr
+
+			for a, v := range tree.Descendants(testCase.prefix) {
+				if !testCase.prefix.Contains(a) {
+					t.Errorf("Descendants(%v) yielded %v outside the prefix", testCase.prefix, a)
+				}
+
+				if v != entries[a] {
+					t.Errorf("Descendants(%v) yielded %v=%q, want %q", testCase.prefix, a, v, entries[a])
+				}
+
+				got = append(got, a)
+			}
+
+			if !slices.Equal(got, testCase.want) {
+				t.Errorf("Descendants(%v) = %v, want %v", testCase.prefix, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestPrefixTree_DescendantsEarlyBreak(t *testing.T) {
+	t.Parallel()
+
+	tree, _ := testHierarchy()
+
+	n := 0
+
+	for range tree.Descendants(New()) {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+
+	if n != 2 {
+		t.Errorf("iterations = %d, want 2", n)
+	}
+}
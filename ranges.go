@@ -0,0 +1,117 @@
+package lattice
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidRange is returned for an AddrRange with min > max or a bound
+// outside [0, MaxCoordValue] other than the -1 wildcard.
+var ErrInvalidRange = errors.New("lattice: invalid range")
+
+// RangeMode selects which ends of an AddrRange are part of the range.
+type RangeMode int
+
+const (
+	// Inclusive ranges contain both bounds: min <= v <= max, as InRange uses.
+	Inclusive RangeMode = iota
+
+	// HalfOpen ranges contain min but not max: min <= v < max. Adjacent
+	// half-open boxes tile space without sharing boundary cells.
+	HalfOpen
+
+	// Exclusive ranges contain neither bound: min < v < max.
+	Exclusive
+)
+
+// InRangeMode is like InRange but with mode choosing whether each bound is
+// included. A -1 bound is unbounded in that direction whatever the mode,
+// e.g. {5,-1} under Exclusive accepts every v > 5.
+// Panics on an unknown mode.
+func (a Addr) InRangeMode(mode RangeMode, ranges ...AddrRange) bool {
+	if mode < Inclusive || mode > Exclusive {
+		panic(fmt.Sprintf("lattice: unknown range mode %d", mode))
+	}
+
+	a.mustMorton()
+
+	coords, dims := a.Coords()
+
+	for i, r := range ranges {
+		if i >= dims {
+			break
+		}
+
+		if !r.containsMode(mode, coords[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Contains reports whether v lies within the inclusive range r, treating a
+// -1 bound as unbounded e.g. AddrRange{5,-1}.Contains(9) → true.
+func (r AddrRange) Contains(v int) bool {
+	return r.containsMode(Inclusive, v)
+}
+
+// Valid reports whether r is well formed: each bound is -1 or within
+// [0, MaxCoordValue], and min <= max when both are set.
+func (r AddrRange) Valid() bool {
+	for _, bound := range r {
+		if bound != -1 && !ValidCoord(bound) {
+			return false
+		}
+	}
+
+	return r[0] == -1 || r[1] == -1 || r[0] <= r[1]
+}
+
+// containsMode reports whether v lies within r under mode.
+func (r AddrRange) containsMode(mode RangeMode, v int) bool {
+	lo, hi := r[0], r[1]
+
+	if lo != -1 && (v < lo || v == lo && mode == Exclusive) {
+		return false
+	}
+
+	return hi == -1 || v < hi || v == hi && mode == Inclusive
+}
+
+// InRangeStrict is like InRange but first validates the ranges, returning
+// an error wrapping ErrInvalidRange if any has min > max (both set) or a
+// bound other than -1 outside [0, MaxCoordValue]. InRange accepts such
+// ranges silently: an inverted range matches nothing, while a negative
+// min or an oversized max acts as an open bound, so {-5,10} matches any
+// v <= 10. Either can hide swapped or miscomputed arguments.
+// Hilbert input returns an error wrapping ErrHilbert instead of panicking.
+func (a Addr) InRangeStrict(ranges ...AddrRange) (bool, error) {
+	if err := a.checkMorton(); err != nil {
+		return false, err
+	}
+
+	for i, r := range ranges {
+		if err := r.check(); err != nil {
+			return false, fmt.Errorf("range[%d]: %w", i, err)
+		}
+	}
+
+	return a.InRange(ranges...), nil
+}
+
+// check returns an error wrapping ErrInvalidRange describing why r is
+// invalid, or nil if r is Valid.
+func (r AddrRange) check() error {
+	if r.Valid() {
+		return nil
+	}
+
+	for _, bound := range r {
+		if bound != -1 && !ValidCoord(bound) {
+			return fmt.Errorf("%w: bound %d not in [0,%d] or -1", ErrInvalidRange, bound, MaxCoordValue)
+		}
+	}
+
+	return fmt.Errorf("%w: min %d > max %d", ErrInvalidRange, r[0], r[1])
+}
@@ -0,0 +1,228 @@
+package lattice
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// ============================================================
+// InRangeMode
+// ============================================================
+
+func TestInRangeMode_Boundaries(t *testing.T) {
+	t.Parallel()
+
+	r := AddrRange{10, 20}
+
+	tests := []struct {
+		v                           int
+		inclusive, halfOpen, exclus bool
+	}{
+		{9, false, false, false},
+		{10, true, true, false},
+		{11, true, true, true},
+		{19, true, true, true},
+		{20, true, false, false},
+		{21, false, false, false},
+	}
+
+	for _, testCase := range tests {
+		addr := New(testCase.v)
+
+		if got := addr.InRangeMode(Inclusive, r); got != testCase.inclusive {
+			t.Errorf("InRangeMode(Inclusive, %v) at %d = %v, want %v", r, testCase.v, got, testCase.inclusive)
+		}
+
+		if got := addr.InRangeMode(HalfOpen, r); got != testCase.halfOpen {
+			t.Errorf("InRangeMode(HalfOpen, %v) at %d = %v, want %v", r, testCase.v, got, testCase.halfOpen)
+		}
+
+		if got := addr.InRangeMode(Exclusive, r); got != testCase.exclus {
+			t.Errorf("InRangeMode(Exclusive, %v) at %d = %v, want %v", r, testCase.v, got, testCase.exclus)
+		}
+
+		if got := addr.InRange(r); got != testCase.inclusive {
+			t.Errorf("InRange(%v) at %d = %v, want Inclusive result %v", r, testCase.v, got, testCase.inclusive)
+		}
+	}
+}
+
+func TestInRangeMode_Wildcards(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		mode RangeMode
+		r    AddrRange
+		v    int
+		want bool
+	}{
+		{"exclusive open top at min", Exclusive, AddrRange{5, -1}, 5, false},
+		{"exclusive open top above", Exclusive, AddrRange{5, -1}, MaxCoordValue, true},
+		{"half-open open bottom at max", HalfOpen, AddrRange{-1, 5}, 5, false},
+		{"half-open open bottom at zero", HalfOpen, AddrRange{-1, 5}, 0, true},
+		{"exclusive full wildcard", Exclusive, AddrRange{-1, -1}, 0, true},
+		{"half-open empty", HalfOpen, AddrRange{7, 7}, 7, false},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := New(testCase.v).InRangeMode(testCase.mode, testCase.r); got != testCase.want {
+				t.Errorf("InRangeMode(%d, %v) at %d = %v, want %v", testCase.mode, testCase.r, testCase.v, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestInRangeMode_HalfOpenTiles(t *testing.T) {
+	t.Parallel()
+
+	// Every cell of [0,16) lands in exactly one of four adjacent half-open tiles.
+	tiles := []AddrRange{{0, 4}, {4, 8}, {8, 12}, {12, 16}}
+
+	for v := range 16 {
+		hits := 0
+
+		for _, tile := range tiles {
+			if New(v, v).InRangeMode(HalfOpen, tile, tile) {
+				hits++
+			}
+		}
+
+		if hits != 1 {
+			t.Errorf("cell %d falls in %d half-open tiles, want 1", v, hits)
+		}
+	}
+}
+
+func TestInRangeMode_PanicUnknownMode(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if got, want := fmt.Sprintf("%v", recover()), "lattice: unknown range mode 7"; got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	New(1).InRangeMode(RangeMode(7), AddrRange{0, 1})
+}
+
+// ============================================================
+// InRangeStrict
+// ============================================================
+
+func TestInRangeStrict(t *testing.T) {
+	t.Parallel()
+
+	addr := New(10, 20)
+
+	tests := []struct {
+		name    string
+		ranges  []AddrRange
+		want    bool
+		wantErr error
+		wantMsg string
+	}{
+		{"valid match", []AddrRange{{5, 15}, {20, 20}}, true, nil, ""},
+		{"valid miss", []AddrRange{{11, 15}}, false, nil, ""},
+		{"wildcards", []AddrRange{{-1, -1}, {-1, 30}}, true, nil, ""},
+		{"inverted", []AddrRange{{0, 100}, {30, 10}}, false, ErrInvalidRange, "range[1]: lattice: invalid range: min 30 > max 10"},
+		{"negative min", []AddrRange{{-5, 10}}, false, ErrInvalidRange, "range[0]: lattice: invalid range: bound -5 not in [0,1048575] or -1"},
+		{"negative bound", []AddrRange{{-2, 5}}, false, ErrInvalidRange, "range[0]: lattice: invalid range: bound -2 not in [0,1048575] or -1"},
+		{"bound too large", []AddrRange{{0, MaxCoordValue + 1}}, false, ErrInvalidRange, "range[0]: lattice: invalid range: bound 1048576 not in [0,1048575] or -1"},
+		{"checked beyond dims", []AddrRange{{0, 10}, {0, 20}, {9, 1}}, false, ErrInvalidRange, "range[2]: lattice: invalid range: min 9 > max 1"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := addr.InRangeStrict(testCase.ranges...)
+			if !errors.Is(err, testCase.wantErr) {
+				t.Fatalf("InRangeStrict() error = %v, want %v", err, testCase.wantErr)
+			}
+
+			if err != nil && err.Error() != testCase.wantMsg {
+				t.Errorf("InRangeStrict() error = %q, want %q", err, testCase.wantMsg)
+			}
+
+			if got != testCase.want {
+				t.Errorf("InRangeStrict() = %v, want %v", got, testCase.want)
+			}
+		})
+	}
+
+	// The lenient InRange reads a negative min as open and an inverted range
+	// as empty; these are the cases InRangeStrict exists to flag.
+	if !addr.InRange(AddrRange{-5, 10}) {
+		t.Error("InRange({-5,10}) = false, want true")
+	}
+
+	if addr.InRange(AddrRange{30, 10}) {
+		t.Error("InRange({30,10}) = true, want false")
+	}
+}
+
+// ============================================================
+// AddrRange.Contains / AddrRange.Valid
+// ============================================================
+
+func TestAddrRange_Contains(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		r    AddrRange
+		v    int
+		want bool
+	}{
+		{AddrRange{5, 10}, 5, true},
+		{AddrRange{5, 10}, 10, true},
+		{AddrRange{5, 10}, 4, false},
+		{AddrRange{5, 10}, 11, false},
+		{AddrRange{5, -1}, MaxCoordValue, true},
+		{AddrRange{5, -1}, 4, false},
+		{AddrRange{-1, 10}, 0, true},
+		{AddrRange{-1, 10}, 11, false},
+		{AddrRange{-1, -1}, 0, true},
+		{AddrRange{-1, -1}, MaxCoordValue, true},
+		{AddrRange{10, 5}, 7, false},
+	}
+
+	for _, testCase := range tests {
+		if got := testCase.r.Contains(testCase.v); got != testCase.want {
+			t.Errorf("%v.Contains(%d) = %v, want %v", testCase.r, testCase.v, got, testCase.want)
+		}
+	}
+}
+
+func TestAddrRange_Valid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		r    AddrRange
+		want bool
+	}{
+		{AddrRange{0, 0}, true},
+		{AddrRange{0, MaxCoordValue}, true},
+		{AddrRange{-1, -1}, true},
+		{AddrRange{-1, 0}, true},
+		{AddrRange{MaxCoordValue, -1}, true},
+		{AddrRange{10, 5}, false},
+		{AddrRange{-2, 5}, false},
+		{AddrRange{0, MaxCoordValue + 1}, false},
+		{AddrRange{MaxCoordValue + 1, -1}, false},
+	}
+
+	for _, testCase := range tests {
+		if got := testCase.r.Valid(); got != testCase.want {
+			t.Errorf("%v.Valid() = %v, want %v", testCase.r, got, testCase.want)
+		}
+
+		if err := testCase.r.check(); (err == nil) != testCase.want {
+			t.Errorf("%v.check() = %v, disagrees with Valid", testCase.r, err)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package lattice
+
+// Set is an unordered collection of distinct addresses.
+// The zero value is an empty set ready to use.
+// A Set must not be copied after first use.
+type Set struct {
+	m map[Addr]struct{}
+}
+
+// Add inserts a into the set.
+func (s *Set) Add(a Addr) {
+	if s.m == nil {
+		s.m = make(map[Addr]struct{})
+	}
+
+	s.m[a] = struct{}{}
+}
+
+// Remove deletes a from the set, if present.
+func (s *Set) Remove(a Addr) {
+	delete(s.m, a)
+}
+
+// Contains reports whether a is in the set.
+func (s *Set) Contains(a Addr) bool {
+	_, ok := s.m[a]
+
+	return ok
+}
+
+// Len returns the number of addresses in the set.
+func (s *Set) Len() int {
+	return len(s.m)
+}
+
+// ContainsAll reports whether every address in addrs is in the set.
+// It is true for an empty addrs.
+func (s *Set) ContainsAll(addrs []Addr) bool {
+	for _, a := range addrs {
+		if !s.Contains(a) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ContainsAny reports whether at least one address in addrs is in the set.
+// It is false for an empty addrs.
+func (s *Set) ContainsAny(addrs []Addr) bool {
+	for _, a := range addrs {
+		if s.Contains(a) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// FilterContained appends to dst, in order, each address of addrs that is
+// in the set, and returns the extended slice. Pass dst[:0] to reuse a
+// buffer; it only grows (allocates) when its capacity is too small.
+func (s *Set) FilterContained(addrs, dst []Addr) []Addr {
+	for _, a := range addrs {
+		if s.Contains(a) {
+			dst = append(dst, a)
+		}
+	}
+
+	return dst
+}
+
+// Union returns a new set holding every address in s or other.
+// Neither operand is modified.
+func (s *Set) Union(other *Set) *Set {
+	out := &Set{m: make(map[Addr]struct{}, len(s.m)+len(other.m))}
+
+	for a := range s.m {
+		out.m[a] = struct{}{}
+	}
+
+	for a := range other.m {
+		out.m[a] = struct{}{}
+	}
+
+	return out
+}
+
+// Intersect returns a new set holding the addresses in both s and other.
+// Neither operand is modified.
+func (s *Set) Intersect(other *Set) *Set {
+	small, large := s, other
+	if len(large.m) < len(small.m) {
+		small, large = large, small
+	}
+
+	out := &Set{m: make(map[Addr]struct{}, len(small.m))}
+
+	for a := range small.m {
+		if _, ok := large.m[a]; ok {
+			out.m[a] = struct{}{}
+		}
+	}
+
+	return out
+}
+
+// Difference returns a new set holding the addresses in s but not in other.
+// Neither operand is modified.
+func (s *Set) Difference(other *Set) *Set {
+	out := &Set{m: make(map[Addr]struct{}, len(s.m))}
+
+	for a := range s.m {
+		if _, ok := other.m[a]; !ok {
+			out.m[a] = struct{}{}
+		}
+	}
+
+	return out
+}
+
+// SymmetricDifference returns a new set holding the addresses in exactly
+// one of s and other. Neither operand is modified.
+func (s *Set) SymmetricDifference(other *Set) *Set {
+	out := &Set{m: make(map[Addr]struct{}, len(s.m)+len(other.m))}
+
+	for a := range s.m {
+		if _, ok := other.m[a]; !ok {
+			out.m[a] = struct{}{}
+		}
+	}
+
+	for a := range other.m {
+		if _, ok := s.m[a]; !ok {
+			out.m[a] = struct{}{}
+		}
+	}
+
+	return out
+}
+
+// IsSubsetOf reports whether every address in s is also in other.
+// Equal sets are subsets of each other, and the empty set is a subset of
+// every set.
+func (s *Set) IsSubsetOf(other *Set) bool {
+	if len(s.m) > len(other.m) {
+		return false
+	}
+
+	for a := range s.m {
+		if _, ok := other.m[a]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsDisjoint reports whether s and other have no address in common.
+func (s *Set) IsDisjoint(other *Set) bool {
+	small, large := s, other
+	if len(large.m) < len(small.m) {
+		small, large = large, small
+	}
+
+	for a := range small.m {
+		if _, ok := large.m[a]; ok {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,265 @@
+package lattice_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/aclivo/lattice"
+)
+
+// newTestSet builds a Set holding addrs.
+func newTestSet(addrs ...lattice.Addr) *lattice.Set {
+	var s lattice.Set
+	for _, a := range addrs {
+		s.Add(a)
+	}
+
+	return &s
+}
+
+// setEqual reports whether s holds exactly addrs.
+func setEqual(s *lattice.Set, addrs ...lattice.Addr) bool {
+	if s.Len() != len(addrs) {
+		return false
+	}
+
+	for _, a := range addrs {
+		if !s.Contains(a) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ============================================================
+// Add / Remove / Contains / Len
+// ============================================================
+
+func TestSet_Basic(t *testing.T) {
+	t.Parallel()
+
+	var s lattice.Set
+
+	if s.Contains(lattice.New(1)) || s.Len() != 0 {
+		t.Error("zero Set is not empty")
+	}
+
+	s.Remove(lattice.New(1)) // no-op on zero Set
+	s.Add(lattice.New(1, 2))
+	s.Add(lattice.New(1, 2)) // duplicate
+	s.Add(lattice.New(3, 4))
+
+	if s.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", s.Len())
+	}
+
+	if !s.Contains(lattice.New(1, 2)) || s.Contains(lattice.New(2, 1)) {
+		t.Error("Contains() reports wrong membership")
+	}
+
+	s.Remove(lattice.New(1, 2))
+
+	if !setEqual(&s, lattice.New(3, 4)) {
+		t.Errorf("after Remove, Len() = %d, want only Addr[3 4]", s.Len())
+	}
+}
+
+// ============================================================
+// ContainsAll / ContainsAny / FilterContained
+// ============================================================
+
+func TestSet_BatchMembership(t *testing.T) {
+	t.Parallel()
+
+	s := newTestSet(lattice.New(1), lattice.New(2), lattice.New(3))
+
+	tests := []struct {
+		name     string
+		addrs    []lattice.Addr
+		all, any bool
+		filtered []lattice.Addr
+	}{
+		{"empty", nil, true, false, nil},
+		{"all members", []lattice.Addr{lattice.New(3), lattice.New(1)}, true, true, []lattice.Addr{lattice.New(3), lattice.New(1)}},
+		{"partial overlap", []lattice.Addr{lattice.New(4), lattice.New(2), lattice.New(5), lattice.New(1)}, false, true, []lattice.Addr{lattice.New(2), lattice.New(1)}},
+		{"no overlap", []lattice.Addr{lattice.New(4), lattice.New(1, 2)}, false, false, nil},
+		{"duplicates kept", []lattice.Addr{lattice.New(2), lattice.New(2)}, true, true, []lattice.Addr{lattice.New(2), lattice.New(2)}},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := s.ContainsAll(testCase.addrs); got != testCase.all {
+				t.Errorf("ContainsAll(%v) = %v, want %v", testCase.addrs, got, testCase.all)
+			}
+
+			if got := s.ContainsAny(testCase.addrs); got != testCase.any {
+				t.Errorf("ContainsAny(%v) = %v, want %v", testCase.addrs, got, testCase.any)
+			}
+
+			if got := s.FilterContained(testCase.addrs, nil); !slices.Equal(got, testCase.filtered) {
+				t.Errorf("FilterContained(%v) = %v, want %v", testCase.addrs, got, testCase.filtered)
+			}
+		})
+	}
+}
+
+func TestSet_BatchMembershipEmptySet(t *testing.T) {
+	t.Parallel()
+
+	var s lattice.Set
+
+	addrs := []lattice.Addr{lattice.New(1), lattice.New(2)}
+
+	if s.ContainsAll(addrs) || s.ContainsAny(addrs) {
+		t.Error("empty set reports members")
+	}
+
+	if !s.ContainsAll(nil) {
+		t.Error("ContainsAll(nil) = false on empty set")
+	}
+
+	if got := s.FilterContained(addrs, nil); len(got) != 0 {
+		t.Errorf("FilterContained on empty set = %v", got)
+	}
+}
+
+func TestSet_FilterContainedAppends(t *testing.T) {
+	t.Parallel()
+
+	s := newTestSet(lattice.New(7), lattice.New(8))
+	dst := make([]lattice.Addr, 1, 4)
+	dst[0] = lattice.New(0)
+
+	got := s.FilterContained([]lattice.Addr{lattice.New(8), lattice.New(9), lattice.New(7)}, dst)
+	if want := []lattice.Addr{lattice.New(0), lattice.New(8), lattice.New(7)}; !slices.Equal(got, want) {
+		t.Errorf("FilterContained = %v, want %v", got, want)
+	}
+
+	if &got[0] != &dst[0] {
+		t.Error("FilterContained reallocated despite spare capacity")
+	}
+}
+
+// ============================================================
+// Union / Intersect / Difference
+// ============================================================
+
+func TestSet_Algebra(t *testing.T) {
+	t.Parallel()
+
+	a1, a2, a3, a4 := lattice.New(1), lattice.New(2), lattice.New(3), lattice.New(4)
+
+	tests := []struct {
+		name                    string
+		left, right             []lattice.Addr
+		union, inter, leftMinus []lattice.Addr
+	}{
+		{"disjoint", []lattice.Addr{a1, a2}, []lattice.Addr{a3, a4}, []lattice.Addr{a1, a2, a3, a4}, nil, []lattice.Addr{a1, a2}},
+		{"overlapping", []lattice.Addr{a1, a2, a3}, []lattice.Addr{a2, a3, a4}, []lattice.Addr{a1, a2, a3, a4}, []lattice.Addr{a2, a3}, []lattice.Addr{a1}},
+		{"identical", []lattice.Addr{a1, a2}, []lattice.Addr{a1, a2}, []lattice.Addr{a1, a2}, []lattice.Addr{a1, a2}, nil},
+		{"left empty", nil, []lattice.Addr{a1}, []lattice.Addr{a1}, nil, nil},
+		{"right empty", []lattice.Addr{a1}, nil, []lattice.Addr{a1}, nil, []lattice.Addr{a1}},
+		{"both empty", nil, nil, nil, nil, nil},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			left := newTestSet(testCase.left...)
+			right := newTestSet(testCase.right...)
+
+			if got := left.Union(right); !setEqual(got, testCase.union...) {
+				t.Errorf("Union() has %d addrs, want %v", got.Len(), testCase.union)
+			}
+
+			if got := left.Intersect(right); !setEqual(got, testCase.inter...) {
+				t.Errorf("Intersect() has %d addrs, want %v", got.Len(), testCase.inter)
+			}
+
+			if got := left.Difference(right); !setEqual(got, testCase.leftMinus...) {
+				t.Errorf("Difference() has %d addrs, want %v", got.Len(), testCase.leftMinus)
+			}
+
+			if !setEqual(left, testCase.left...) || !setEqual(right, testCase.right...) {
+				t.Error("set operation mutated an operand")
+			}
+		})
+	}
+}
+
+func TestSet_ResultIsIndependent(t *testing.T) {
+	t.Parallel()
+
+	left := newTestSet(lattice.New(1))
+	right := newTestSet(lattice.New(2))
+
+	union := left.Union(right)
+	union.Add(lattice.New(3))
+	union.Remove(lattice.New(1))
+
+	if !setEqual(left, lattice.New(1)) || !setEqual(right, lattice.New(2)) {
+		t.Error("mutating a Union result changed an operand")
+	}
+}
+
+// ============================================================
+// SymmetricDifference / IsSubsetOf / IsDisjoint
+// ============================================================
+
+func TestSet_Predicates(t *testing.T) {
+	t.Parallel()
+
+	a1, a2, a3, a4 := lattice.New(1), lattice.New(2), lattice.New(3), lattice.New(4)
+
+	tests := []struct {
+		name              string
+		left, right       []lattice.Addr
+		symDiff           []lattice.Addr
+		leftSub, rightSub bool
+		disjoint          bool
+	}{
+		{"proper subset", []lattice.Addr{a1, a2}, []lattice.Addr{a1, a2, a3}, []lattice.Addr{a3}, true, false, false},
+		{"equal", []lattice.Addr{a1, a2}, []lattice.Addr{a2, a1}, nil, true, true, false},
+		{"disjoint", []lattice.Addr{a1, a2}, []lattice.Addr{a3, a4}, []lattice.Addr{a1, a2, a3, a4}, false, false, true},
+		{"overlapping", []lattice.Addr{a1, a2, a3}, []lattice.Addr{a2, a3, a4}, []lattice.Addr{a1, a4}, false, false, false},
+		{"left empty", nil, []lattice.Addr{a1}, []lattice.Addr{a1}, true, false, true},
+		{"both empty", nil, nil, nil, true, true, true},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			left := newTestSet(testCase.left...)
+			right := newTestSet(testCase.right...)
+
+			if got := left.SymmetricDifference(right); !setEqual(got, testCase.symDiff...) {
+				t.Errorf("SymmetricDifference() has %d addrs, want %v", got.Len(), testCase.symDiff)
+			}
+
+			if got := right.SymmetricDifference(left); !setEqual(got, testCase.symDiff...) {
+				t.Errorf("reversed SymmetricDifference() has %d addrs, want %v", got.Len(), testCase.symDiff)
+			}
+
+			if got := left.IsSubsetOf(right); got != testCase.leftSub {
+				t.Errorf("left.IsSubsetOf(right) = %v, want %v", got, testCase.leftSub)
+			}
+
+			if got := right.IsSubsetOf(left); got != testCase.rightSub {
+				t.Errorf("right.IsSubsetOf(left) = %v, want %v", got, testCase.rightSub)
+			}
+
+			if got := left.IsDisjoint(right); got != testCase.disjoint {
+				t.Errorf("IsDisjoint() = %v, want %v", got, testCase.disjoint)
+			}
+
+			if !setEqual(left, testCase.left...) || !setEqual(right, testCase.right...) {
+				t.Error("set operation mutated an operand")
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package lattice
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// signedOffset shifts signed coordinates onto [0, MaxCoordValue].
+	signedOffset = 1 << (BitsPerCoord - 1)
+
+	// MinSignedCoord is the smallest coordinate NewSigned accepts (-524,288).
+	MinSignedCoord = -signedOffset
+
+	// MaxSignedCoord is the largest coordinate NewSigned accepts (524,287).
+	MaxSignedCoord = signedOffset - 1
+)
+
+// NewSigned creates an Addr from coordinates that may be negative, such as
+// grids centered on the origin. Each coordinate in
+// [MinSignedCoord, MaxSignedCoord] = [-524288, 524287] is offset by 524288
+// onto [0, MaxCoordValue] before Z-order encoding, so ordering and locality
+// carry across zero.
+//
+// The address carries a header flag, so a signed address never equals an
+// unsigned one as a map key; with no coordinates there is nothing to flag,
+// so NewSigned() is New(). Decode with SignedCoords; Coords, At and other
+// unsigned methods see the offset values. Transforms such as Add, With,
+// Slice and Translate operate on those offset values and keep the flag, so
+// NewSigned(-1, 2).Add(1) is NewSigned(0, 2); those the offset would skew,
+// such as Scale and Quantize, panic or return ErrSigned instead.
+// Panics if more than MaxDimensions coordinates are provided or any
+// coordinate is outside the signed range.
+func NewSigned(coords ...int) Addr {
+	if len(coords) > MaxDimensions {
+		panic(fmt.Sprintf("lattice: max %d dimensions supported", MaxDimensions))
+	}
+
+	var offset Buffer
+
+	for i, v := range coords {
+		if v < MinSignedCoord || v > MaxSignedCoord {
+			panic(fmt.Sprintf("lattice: coord[%d]=%d out of range [%d,%d]", i, v, MinSignedCoord, MaxSignedCoord))
+		}
+
+		offset[i] = v + signedOffset
+	}
+
+	return New(offset[:len(coords)]...).withFlags(Addr{flagWord: flagSigned})
+}
+
+// ErrSigned is returned when a method that scales, buckets or measures
+// coordinates from the origin receives an address built by NewSigned, whose
+// stored values carry the signed offset; the panicking counterparts of such
+// methods panic with its message.
+var ErrSigned = errors.New("lattice: address is signed")
+
+// checkPlain returns an error wrapping ErrHilbert or ErrSigned unless a is
+// an unflagged Morton address.
+func (a Addr) checkPlain() error {
+	if err := a.checkMorton(); err != nil {
+		return err
+	}
+
+	if a.IsSigned() {
+		return fmt.Errorf("%w: %#v", ErrSigned, a)
+	}
+
+	return nil
+}
+
+// mustPlain panics with the checkPlain error unless a is an unflagged
+// Morton address.
+func (a Addr) mustPlain() {
+	if err := a.checkPlain(); err != nil {
+		panic(err.Error())
+	}
+}
+
+// IsSigned reports whether a was built by NewSigned.
+func (a Addr) IsSigned() bool {
+	return a[flagWord]&flagSigned != 0
+}
+
+// SignedCoords decodes an address built by NewSigned, returning its signed
+// coordinates and their count.
+// Panics if a has coordinates and is not signed; a 0-dimension address,
+// such as NewSigned(), decodes to no coordinates.
+func (a Addr) SignedCoords() (Buffer, int) {
+	if !a.IsSigned() && a.Dims() > 0 {
+		panic(fmt.Sprintf("lattice: %v is not signed", a))
+	}
+
+	coords, dims := a.Coords()
+	for i := range dims {
+		coords[i] -= signedOffset
+	}
+
+	return coords, dims
+}
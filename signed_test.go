@@ -0,0 +1,267 @@
+package lattice
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// ============================================================
+// NewSigned / SignedCoords
+// ============================================================
+
+func TestSigned_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		coords []int
+	}{
+		{"empty", []int{}},
+		{"zero", []int{0}},
+		{"extremes", []int{MinSignedCoord, MaxSignedCoord}},
+		{"around zero", []int{-1, 0, 1}},
+		{"mixed", []int{-524288, -12345, 0, 12345, 524287, -1}},
+		{"max dims", []int{-6, -5, -4, -3, -2, -1, 0, 1, 2, 3, 4, 5}},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			addr := NewSigned(testCase.coords...)
+
+			// With no coordinates there is nothing to flag: NewSigned() is New().
+			if addr.IsSigned() != (len(testCase.coords) > 0) || addr.Dims() != len(testCase.coords) {
+				t.Fatalf("IsSigned() = %v, Dims() = %d", addr.IsSigned(), addr.Dims())
+			}
+
+			got, dims := addr.SignedCoords()
+			if !slices.Equal(got[:dims], testCase.coords) {
+				t.Errorf("SignedCoords() = %v, want %v", got[:dims], testCase.coords)
+			}
+
+			var decoded Addr
+			if data, _ := addr.MarshalBinary(); decoded.UnmarshalBinary(data) != nil || decoded != addr {
+				t.Errorf("binary round trip = %v, want %v", decoded, addr)
+			}
+		})
+	}
+}
+
+func TestSigned_FullRangeSweep(t *testing.T) {
+	t.Parallel()
+
+	for v := MinSignedCoord; v <= MaxSignedCoord; v += 997 {
+		got, _ := NewSigned(v, -v-1).SignedCoords()
+		if got[0] != v || got[1] != -v-1 {
+			t.Fatalf("SignedCoords(NewSigned(%d, %d)) = %v", v, -v-1, got[:2])
+		}
+	}
+}
+
+func TestSigned_DistinctFromUnsigned(t *testing.T) {
+	t.Parallel()
+
+	// NewSigned(0) stores 524288, the same bits New(524288) stores.
+	if NewSigned(0) == New(signedOffset) {
+		t.Error("NewSigned(0) equals New(524288)")
+	}
+
+	if New(1).IsSigned() || NewHilbert(1).IsSigned() || NewSigned(1).IsHilbert() {
+		t.Error("encoding flags leak between constructors")
+	}
+
+	if _, err := FromWords(NewSigned(-3, 3).Words()); err != nil {
+		t.Errorf("FromWords(NewSigned()) error = %v", err)
+	}
+
+	if _, err := NewSigned(-1).MarshalText(); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("MarshalText() error = %v, want %v", err, errors.ErrUnsupported)
+	}
+}
+
+func TestSigned_Panics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		fn      func()
+		wantMsg string
+	}{
+		{"below min", func() { NewSigned(0, MinSignedCoord-1) }, "lattice: coord[1]=-524289 out of range [-524288,524287]"},
+		{"above max", func() { NewSigned(MaxSignedCoord + 1) }, "lattice: coord[0]=524288 out of range [-524288,524287]"},
+		{"too many dims", func() { NewSigned(make([]int, 13)...) }, "lattice: max 12 dimensions supported"},
+		{"not signed", func() { New(1).SignedCoords() }, "lattice: Addr[1] is not signed"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != testCase.wantMsg {
+					t.Errorf("panic message = %q, want %q", got, testCase.wantMsg)
+				}
+			}()
+
+			testCase.fn()
+		})
+	}
+}
+
+// ============================================================
+// Transforms keep the signed flag
+// ============================================================
+
+func TestSigned_TransformsKeepFlag(t *testing.T) {
+	t.Parallel()
+
+	a := NewSigned(-1, 2)
+	b := NewSigned(-3, 4)
+	m := map[Addr]int{a: 1}
+
+	first := func(addrs []Addr) Addr { return addrs[0] }
+	drop := func(addr Addr, _ bool) Addr { return addr }
+	must := func(addr Addr, err error) Addr {
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		return addr
+	}
+	key := func(m map[Addr]int) Addr {
+		for k := range m {
+			return k
+		}
+
+		return Addr{}
+	}
+	boxSecond := func() Addr {
+		var out []Addr
+		for cell := range Box(a, NewSigned(0, 2)) {
+			out = append(out, cell)
+		}
+
+		return out[1]
+	}
+	lo, hi, _ := BoundingBox([]Addr{a, b})
+
+	// Transforms work on the stored values, which are offset by
+	// signedOffset, so raw arguments such as With values carry it too.
+	tests := []struct {
+		name string
+		got  Addr
+		want Addr
+	}{
+		{"Add", a.Add(1), NewSigned(0, 2)},
+		{"Sub", a.Sub(0, 1), NewSigned(-1, 1)},
+		{"TryAdd", must(a.TryAdd(1)), NewSigned(0, 2)},
+		{"TrySub", must(a.TrySub(1)), NewSigned(-2, 2)},
+		{"Incr", drop(a.Incr(0)), NewSigned(0, 2)},
+		{"Decr", drop(a.Decr(1)), NewSigned(-1, 1)},
+		{"With", a.With(1, signedOffset+5), NewSigned(-1, 5)},
+		{"TryWith", must(a.TryWith(0, signedOffset)), NewSigned(0, 2)},
+		{"WithCoords", a.WithCoords([2]int{0, signedOffset + 4}), NewSigned(4, 2)},
+		{"Min", a.Min(b), NewSigned(-3, 2)},
+		{"Max", a.Max(b), NewSigned(-1, 4)},
+		{"Reverse", a.Reverse(), NewSigned(2, -1)},
+		{"Swap", a.Swap(0, 1), NewSigned(2, -1)},
+		{"Permute", a.Permute([]int{1, 0}), NewSigned(2, -1)},
+		{"Map", a.Map(func(_, v int) int { return v + 1 }), NewSigned(0, 3)},
+		{"Slice", a.Slice(1, 2), NewSigned(2)},
+		{"TrySlice", must(a.TrySlice(0, 1)), NewSigned(-1)},
+		{"Truncate", a.Truncate(1), NewSigned(-1)},
+		{"Parent", a.Parent(), NewSigned(-1)},
+		{"CommonPrefix", a.CommonPrefix(NewSigned(-1, 7)), NewSigned(-1)},
+		{"Child", a.Child(signedOffset + 7), NewSigned(-1, 2, 7)},
+		{"Append", a.Append(signedOffset), NewSigned(-1, 2, 0)},
+		{"AppendBuf", a.AppendBuf(nil, signedOffset), NewSigned(-1, 2, 0)},
+		{"TryAppend", must(a.TryAppend(signedOffset)), NewSigned(-1, 2, 0)},
+		{"Pad", a.Pad(3, signedOffset), NewSigned(-1, 2, 0)},
+		{"SetDims", a.SetDims(3, signedOffset), NewSigned(-1, 2, 0)},
+		{"StepWrap", a.StepWrap(0, 1, MaxCoordValue+1), NewSigned(0, 2)},
+		{"Neighbors", first(a.Neighbors(nil)), NewSigned(-2, 2)},
+		{"NeighborsWrap", first(a.NeighborsWrap(MaxCoordValue+1, nil)), NewSigned(-2, 2)},
+		{"MooreNeighbors", first(a.MooreNeighbors(nil)), NewSigned(-2, 1)},
+		{"BoundingBox lo", lo, NewSigned(-3, 2)},
+		{"BoundingBox hi", hi, NewSigned(-1, 4)},
+		{"Box", boxSecond(), NewSigned(0, 2)},
+		{"Lerp", Lerp(a, NewSigned(1, 2), 0.5), NewSigned(0, 2)},
+		{"Midpoint", Midpoint(a, NewSigned(1, 2)), NewSigned(0, 2)},
+		{"Line", Line(a, NewSigned(1, 2), nil)[1], NewSigned(0, 2)},
+		{"Translate", key(Translate(m, 1)), NewSigned(0, 2)},
+		{"Flip", key(Flip(m, 0, 2*signedOffset)), NewSigned(1, 2)},
+		{"RollUp", key(RollUp(map[Addr]int{NewSigned(-1, 2, 3): 1}, 1)), NewSigned(-1, 3)},
+	}
+
+	for _, testCase := range tests {
+		if !testCase.got.IsSigned() || testCase.got != testCase.want {
+			t.Errorf("%s = %#v (signed %v), want %#v", testCase.name, testCase.got, testCase.got.IsSigned(), testCase.want)
+		}
+	}
+}
+
+func TestSigned_ZeroDimResultUnflagged(t *testing.T) {
+	t.Parallel()
+
+	a := NewSigned(-1, 2)
+
+	for name, got := range map[string]Addr{
+		"Slice(0, 0)": a.Slice(0, 0),
+		"Truncate(0)": a.Truncate(0),
+		"SetDims(0)":  a.SetDims(0, 0),
+		"Parent()":    NewSigned(5).Parent(),
+	} {
+		if got != New() {
+			t.Errorf("%s = %x, want New()", name, got.Words())
+		}
+	}
+}
+
+// ============================================================
+// Scaling and measuring reject signed input
+// ============================================================
+
+// The stored values carry the signed offset, so scaling, bucketing or
+// measuring them from the origin would yield wrong signed coordinates.
+func TestSigned_ScalingMethodsPanic(t *testing.T) {
+	t.Parallel()
+
+	a := NewSigned(-3, 2)
+	want := "lattice: address is signed: lattice.NewSigned(-3, 2)"
+
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{"Scale", func() { a.Scale(2) }},
+		{"Quantize", func() { a.Quantize(4) }},
+		{"Clamp", func() { a.Clamp(AddrRange{-5, 5}) }},
+		{"Span", func() { a.Span() }},
+		{"MaxCoord", func() { a.MaxCoord() }},
+		{"Bounds", func() { a.Bounds() }},
+		{"FixedCoords", func() { a.FixedCoords(10) }},
+		{"Downsample", func() { Downsample(map[Addr]int{a: 1}, 2, func(acc, v int) int { return acc + v }) }},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != want {
+					t.Errorf("panic = %q, want %q", got, want)
+				}
+			}()
+
+			testCase.fn()
+		})
+	}
+
+	got, err := a.TryScale(2)
+	if !errors.Is(err, ErrSigned) || got != a {
+		t.Errorf("TryScale(2) = %v, %v, want the receiver and %v", got, err, ErrSigned)
+	}
+}
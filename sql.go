@@ -0,0 +1,27 @@
+package lattice
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Value implements driver.Valuer, storing the address in its compact
+// MarshalBinary form, suitable for BYTEA / BLOB columns.
+func (a Addr) Value() (driver.Value, error) {
+	return a.MarshalBinary()
+}
+
+// Scan implements sql.Scanner. src must be the MarshalBinary form as a
+// []byte, or as a string for drivers that report binary columns as text.
+// NULL is rejected; use sql.Null[Addr] for nullable columns.
+// Returns an error wrapping ErrMalformed or ErrVersion on invalid data.
+func (a *Addr) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return a.UnmarshalBinary(v)
+	case string:
+		return a.UnmarshalBinary([]byte(v))
+	default:
+		return fmt.Errorf("%w: cannot scan %T into Addr", ErrMalformed, src)
+	}
+}
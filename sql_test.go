@@ -0,0 +1,90 @@
+package lattice_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/aclivo/lattice"
+)
+
+// Compile-time interface checks.
+var (
+	_ driver.Valuer = lattice.Addr{}
+	_ sql.Scanner   = (*lattice.Addr)(nil)
+)
+
+// ============================================================
+// Value / Scan
+// ============================================================
+
+func TestSQL_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	addrs := []lattice.Addr{
+		lattice.New(),
+		lattice.New(1, 2, 3),
+		lattice.New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12),
+	}
+
+	for _, addr := range addrs {
+		value, err := addr.Value()
+		if err != nil {
+			t.Fatalf("Value() error = %v", err)
+		}
+
+		if !driver.IsValue(value) {
+			t.Fatalf("Value() = %T, not a valid driver.Value", value)
+		}
+
+		data, ok := value.([]byte)
+		if !ok {
+			t.Fatalf("Value() = %T, want []byte", value)
+		}
+
+		for name, src := range map[string]any{"bytes": data, "string": string(data)} {
+			var got lattice.Addr
+			if err := got.Scan(src); err != nil {
+				t.Fatalf("Scan(%s) error = %v", name, err)
+			}
+
+			if !got.Equal(addr) {
+				t.Errorf("Scan(%s) = %v, want %v", name, got, addr)
+			}
+		}
+	}
+}
+
+func TestSQL_ScanRejects(t *testing.T) {
+	t.Parallel()
+
+	valid, err := lattice.New(1, 2, 3).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		src     any
+		wantErr error
+	}{
+		{"null", nil, lattice.ErrMalformed},
+		{"int64", int64(5), lattice.ErrMalformed},
+		{"truncated", valid[:len(valid)-2], lattice.ErrMalformed},
+		{"over-long", append(append([]byte{}, valid...), 0, 0), lattice.ErrMalformed},
+		{"bad version", append([]byte{0}, valid[1:]...), lattice.ErrVersion},
+		{"empty string", "", lattice.ErrMalformed},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			var addr lattice.Addr
+			if err := addr.Scan(testCase.src); !errors.Is(err, testCase.wantErr) {
+				t.Errorf("Scan() error = %v, want %v", err, testCase.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package lattice
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"sync"
+)
+
+const (
+	// maxBinaryLen is the longest MarshalBinary form, for MaxDimensions coordinates.
+	maxBinaryLen = binaryHeaderLen + (MaxDimensions*BitsPerCoord+bitsPerByte-1)/bitsPerByte
+
+	// streamVersion identifies the delta stream layout written by StreamWriter.
+	streamVersion = 1
+
+	// varintBits is the number of payload bits in each varint byte.
+	varintBits = 7
+
+	// varintMore is set on every varint byte except the last.
+	varintMore = 0x80
+
+	// maxVarintLen is the longest varint encoding of a 256-bit delta.
+	maxVarintLen = (addrBits + varintBits - 1) / varintBits
+)
+
+// ErrOrder is returned by StreamWriter.Write for an address that sorts
+// before the previous one.
+var ErrOrder = errors.New("lattice: address out of order")
+
+// recordPool recycles record buffers; handing a stack array to an
+// io.Writer or io.Reader would force it to escape on every call.
+var recordPool = sync.Pool{New: func() any { return new([maxBinaryLen]byte) }}
+
+// WriteTo implements io.WriterTo, writing the MarshalBinary form of a to w.
+//
+// Each record is self-describing: byte 0 is the format version and the low
+// bits of byte 1 the dimension count, which fixes the number of packed
+// coordinate bytes that follow (see MarshalBinary). Records can therefore be
+// concatenated into a stream with no further framing and read back one at a
+// time with ReadAddr.
+func (a Addr) WriteTo(w io.Writer) (int64, error) {
+	buf := recordPool.Get().(*[maxBinaryLen]byte) //nolint:forcetypeassert // pool only holds record buffers
+	defer recordPool.Put(buf)
+
+	data, _ := a.AppendBinary(buf[:0]) //nolint:errcheck // AppendBinary never fails
+	n, err := w.Write(data)
+
+	return int64(n), err
+}
+
+// ReadAddr reads one address written by WriteTo or MarshalBinary from r.
+// It returns io.EOF if r is exhausted before the record starts,
+// io.ErrUnexpectedEOF if the stream ends mid-record, and an error wrapping
+// ErrVersion or ErrMalformed for a corrupt record.
+func ReadAddr(r io.Reader) (Addr, error) {
+	buf := recordPool.Get().(*[maxBinaryLen]byte) //nolint:forcetypeassert // pool only holds record buffers
+	defer recordPool.Put(buf)
+
+	if _, err := io.ReadFull(r, buf[:binaryHeaderLen]); err != nil {
+		return Addr{}, err
+	}
+
+	if buf[0] != binaryVersion {
+		return Addr{}, fmt.Errorf("%w: %d", ErrVersion, buf[0])
+	}
+
+	dims := int(buf[1] &^ binaryFlagsMask)
+	if dims > MaxDimensions {
+		return Addr{}, fmt.Errorf("%w: %d dimensions exceeds max %d", ErrMalformed, dims, MaxDimensions)
+	}
+
+	n := binaryHeaderLen + packedLen(dims)
+
+	if _, err := io.ReadFull(r, buf[binaryHeaderLen:n]); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+
+		return Addr{}, err
+	}
+
+	var addr Addr
+	if err := addr.UnmarshalBinary(buf[:n]); err != nil {
+		return Addr{}, err
+	}
+
+	return addr, nil
+}
+
+// StreamWriter writes addresses in non-decreasing Compare order as a
+// compact delta stream. After a version byte, each address is stored as the
+// difference of its raw 256-bit encoding from the previous one (the first
+// from zero) in unsigned LEB128 varint form: 7 bits per byte, low group
+// first, high bit set on all but the last byte. Nearby Morton-sorted
+// addresses share their high bits, so deltas are typically a few bytes
+// instead of 32.
+type StreamWriter struct {
+	w       io.Writer
+	prev    Addr
+	started bool
+	buf     [1 + maxVarintLen]byte
+}
+
+// NewStreamWriter returns a StreamWriter writing to w.
+func NewStreamWriter(w io.Writer) *StreamWriter {
+	return &StreamWriter{w: w}
+}
+
+// Write appends a to the stream. It returns an error wrapping ErrOrder,
+// writing nothing, if a sorts before the previously written address.
+func (sw *StreamWriter) Write(a Addr) error {
+	if a.Compare(sw.prev) < 0 {
+		return fmt.Errorf("%w: %v after %v", ErrOrder, a, sw.prev)
+	}
+
+	n := 0
+	if !sw.started {
+		sw.buf[0] = streamVersion
+		n = 1
+	}
+
+	delta := sub256(a, sw.prev)
+
+	for {
+		b := byte(delta[0]) &^ varintMore
+		delta = shr256(delta, varintBits)
+
+		if delta == (Addr{}) {
+			sw.buf[n] = b
+			n++
+
+			break
+		}
+
+		sw.buf[n] = b | varintMore
+		n++
+	}
+
+	if _, err := sw.w.Write(sw.buf[:n]); err != nil {
+		return err
+	}
+
+	sw.prev, sw.started = a, true
+
+	return nil
+}
+
+// StreamReader reads addresses written by a StreamWriter.
+type StreamReader struct {
+	r       io.ByteReader
+	prev    Addr
+	started bool
+}
+
+// NewStreamReader returns a StreamReader reading from r, buffering it
+// unless r already implements io.ByteReader.
+func NewStreamReader(r io.Reader) *StreamReader {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	return &StreamReader{r: br}
+}
+
+// Read returns the next address in the stream. It returns io.EOF at the
+// clean end of the stream, io.ErrUnexpectedEOF if it ends mid-record, and
+// an error wrapping ErrVersion or ErrMalformed for corrupt data.
+func (sr *StreamReader) Read() (Addr, error) {
+	if !sr.started {
+		version, err := sr.r.ReadByte()
+		if err != nil {
+			return Addr{}, err
+		}
+
+		if version != streamVersion {
+			return Addr{}, fmt.Errorf("%w: stream version %d", ErrVersion, version)
+		}
+
+		sr.started = true
+	}
+
+	var delta Addr
+
+	for i := 0; ; i++ {
+		b, err := sr.r.ReadByte()
+		if err != nil {
+			if i > 0 && errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+
+			return Addr{}, err
+		}
+
+		group := uint64(b &^ varintMore)
+		if pos := i * varintBits; pos >= addrBits || group>>(addrBits-pos) != 0 {
+			return Addr{}, fmt.Errorf("%w: delta exceeds 256 bits", ErrMalformed)
+		}
+
+		delta.orBits(i*varintBits, group)
+
+		if b&varintMore == 0 {
+			break
+		}
+	}
+
+	addr, carry := add256(sr.prev, delta)
+	if carry {
+		return Addr{}, fmt.Errorf("%w: delta overflows 256 bits", ErrMalformed)
+	}
+
+	if err := addr.check(); err != nil {
+		return Addr{}, err
+	}
+
+	sr.prev = addr
+
+	return addr, nil
+}
+
+// add256 returns a+b as 256-bit integers, low word first, and whether the
+// sum overflowed.
+func add256(a, b Addr) (Addr, bool) {
+	var (
+		sum   Addr
+		carry uint64
+	)
+
+	for i := range a {
+		sum[i], carry = bits.Add64(a[i], b[i], carry)
+	}
+
+	return sum, carry != 0
+}
+
+// sub256 returns a-b as 256-bit integers, low word first. a must not be
+// less than b.
+func sub256(a, b Addr) Addr {
+	var (
+		diff   Addr
+		borrow uint64
+	)
+
+	for i := range a {
+		diff[i], borrow = bits.Sub64(a[i], b[i], borrow)
+	}
+
+	return diff
+}
+
+// shr256 returns a shifted right by n < 64 bits as a 256-bit integer.
+func shr256(a Addr, n int) Addr {
+	for i := range len(a) - 1 {
+		a[i] = a[i]>>n | a[i+1]<<(bitsPerWord-n)
+	}
+
+	a[len(a)-1] >>= n
+
+	return a
+}
@@ -0,0 +1,336 @@
+package lattice
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+)
+
+// ============================================================
+// WriteTo / ReadAddr
+// ============================================================
+
+func TestWriteTo_ReadAddr_Stream(t *testing.T) {
+	t.Parallel()
+
+	addrs := []Addr{
+		New(),
+		New(7),
+		New(1, 2, 3),
+		New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12),
+		NewHilbert(3, 4),
+		NewSigned(-5, 5),
+		New(MaxCoordValue, 0),
+	}
+
+	var (
+		stream  bytes.Buffer
+		written int64
+	)
+
+	for _, addr := range addrs {
+		n, err := addr.WriteTo(&stream)
+		if err != nil {
+			t.Fatalf("WriteTo() error = %v", err)
+		}
+
+		want, _ := addr.MarshalBinary()
+		if n != int64(len(want)) {
+			t.Errorf("WriteTo(%v) = %d bytes, want %d", addr, n, len(want))
+		}
+
+		written += n
+	}
+
+	if int64(stream.Len()) != written {
+		t.Errorf("stream has %d bytes, WriteTo reported %d", stream.Len(), written)
+	}
+
+	for i, want := range addrs {
+		got, err := ReadAddr(&stream)
+		if err != nil {
+			t.Fatalf("ReadAddr() #%d error = %v", i, err)
+		}
+
+		if got != want {
+			t.Errorf("ReadAddr() #%d = %v, want %v", i, got, want)
+		}
+	}
+
+	if _, err := ReadAddr(&stream); !errors.Is(err, io.EOF) {
+		t.Errorf("ReadAddr() at end error = %v, want %v", err, io.EOF)
+	}
+}
+
+func TestReadAddr_Truncated(t *testing.T) {
+	t.Parallel()
+
+	full, _ := New(1, 2, 3).MarshalBinary()
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"empty", nil, io.EOF},
+		{"half header", full[:1], io.ErrUnexpectedEOF},
+		{"header only", full[:2], io.ErrUnexpectedEOF},
+		{"short payload", full[:len(full)-1], io.ErrUnexpectedEOF},
+		{"bad version", append([]byte{9}, full[1:]...), ErrVersion},
+		{"too many dims", []byte{binaryVersion, 13}, ErrMalformed},
+		{"non-zero padding", []byte{binaryVersion, 1, 0, 0, 0xF0}, ErrMalformed},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := ReadAddr(bytes.NewReader(testCase.data)); !errors.Is(err, testCase.wantErr) {
+				t.Errorf("ReadAddr() error = %v, want %v", err, testCase.wantErr)
+			}
+		})
+	}
+}
+
+// errWriter fails every write.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) { return 0, io.ErrClosedPipe }
+
+func TestWriteTo_PropagatesError(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(1).WriteTo(errWriter{}); !errors.Is(err, io.ErrClosedPipe) {
+		t.Errorf("WriteTo() error = %v, want %v", err, io.ErrClosedPipe)
+	}
+}
+
+//nolint:paralleltest // testing.AllocsPerRun cannot run in parallel tests
+func TestWriteTo_ReadAddr_ZeroAllocs(t *testing.T) {
+	addr := New(123456, 7890, 42)
+
+	var stream bytes.Buffer
+
+	stream.Grow(64)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = addr.WriteTo(&stream)
+		_, _ = ReadAddr(&stream)
+	})
+	if allocs != 0 {
+		t.Errorf("WriteTo/ReadAddr allocs = %v, want 0", allocs)
+	}
+}
+
+func BenchmarkWriteTo_3D(b *testing.B) {
+	addr := New(123456, 7890, 42)
+
+	b.ReportAllocs()
+
+	for b.Loop() {
+		_, _ = addr.WriteTo(io.Discard)
+	}
+}
+
+// ============================================================
+// StreamWriter / StreamReader
+// ============================================================
+
+// writeStream writes addrs to a new delta stream and returns its bytes.
+func writeStream(t *testing.T, addrs []Addr) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	sw := NewStreamWriter(&buf)
+	for _, a := range addrs {
+		if err := sw.Write(a); err != nil {
+			t.Fatalf("Write(%v) error = %v", a, err)
+		}
+	}
+
+	return buf.Bytes()
+}
+
+func TestStream_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	addrs := []Addr{New(), New(0), New(5), New(1, 2), New(1, 2), New(0, 0, 0)}
+	for a := range Box(New(10, 10, 10), New(17, 17, 17)) {
+		addrs = append(addrs, a)
+	}
+
+	addrs = append(addrs,
+		New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12),
+		New(MaxCoordValue, MaxCoordValue, MaxCoordValue, MaxCoordValue,
+			MaxCoordValue, MaxCoordValue, MaxCoordValue, MaxCoordValue,
+			MaxCoordValue, MaxCoordValue, MaxCoordValue, MaxCoordValue),
+		NewSigned(1, 2),
+		NewHilbert(1, 2),
+	)
+	slices.SortFunc(addrs, Addr.Compare)
+
+	data := writeStream(t, addrs)
+	sr := NewStreamReader(bytes.NewReader(data))
+
+	for i, want := range addrs {
+		got, err := sr.Read()
+		if err != nil {
+			t.Fatalf("Read() #%d error = %v", i, err)
+		}
+
+		if got != want {
+			t.Errorf("Read() #%d = %v, want %v", i, got, want)
+		}
+	}
+
+	if _, err := sr.Read(); !errors.Is(err, io.EOF) {
+		t.Errorf("Read() at end error = %v, want %v", err, io.EOF)
+	}
+}
+
+func TestStream_Compresses(t *testing.T) {
+	t.Parallel()
+
+	var addrs []Addr
+	for a := range Box(New(100, 200, 300), New(131, 231, 331)) {
+		addrs = append(addrs, a)
+	}
+
+	slices.SortFunc(addrs, Addr.Compare)
+
+	data := writeStream(t, addrs)
+
+	// Consecutive Morton neighbors differ by small deltas: mostly 1 byte.
+	if perAddr := float64(len(data)) / float64(len(addrs)); perAddr > 2 {
+		t.Errorf("stream uses %.2f bytes per address, want <= 2", perAddr)
+	}
+}
+
+func TestStreamWriter_RejectsOutOfOrder(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	sw := NewStreamWriter(&buf)
+	if err := sw.Write(New(5, 5)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	written := buf.Len()
+
+	if err := sw.Write(New(4, 5)); !errors.Is(err, ErrOrder) {
+		t.Errorf("Write(out of order) error = %v, want %v", err, ErrOrder)
+	}
+
+	if buf.Len() != written {
+		t.Errorf("rejected Write wrote %d bytes", buf.Len()-written)
+	}
+
+	// The writer is still usable after a rejected address.
+	if err := sw.Write(New(5, 6)); err != nil {
+		t.Errorf("Write() after rejection error = %v", err)
+	}
+
+	sr := NewStreamReader(&buf)
+	for _, want := range []Addr{New(5, 5), New(5, 6)} {
+		if got, err := sr.Read(); err != nil || got != want {
+			t.Errorf("Read() = %v, %v, want %v", got, err, want)
+		}
+	}
+}
+
+func TestStreamReader_Rejects(t *testing.T) {
+	t.Parallel()
+
+	valid := writeStream(t, []Addr{New(1, 2, 3)})
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{"empty", nil, io.EOF},
+		{"bad version", append([]byte{9}, valid[1:]...), ErrVersion},
+		{"truncated varint", valid[:len(valid)-1], io.ErrUnexpectedEOF},
+		{"stray bits", strayBitStream(), ErrMalformed},
+		{"delta too long", append([]byte{streamVersion}, bytes.Repeat([]byte{0xFF}, 38)...), ErrMalformed},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := NewStreamReader(bytes.NewReader(testCase.data)).Read(); !errors.Is(err, testCase.wantErr) {
+				t.Errorf("Read() error = %v, want %v", err, testCase.wantErr)
+			}
+		})
+	}
+}
+
+// strayBitStream returns a stream whose single delta decodes to a
+// 1-dimension address with bit 200 set, far beyond its coordinate.
+func strayBitStream() []byte {
+	data := []byte{streamVersion, 0x01 | varintMore}
+	data = append(data, bytes.Repeat([]byte{varintMore}, 200/varintBits-1)...)
+
+	return append(data, 1<<(200%varintBits))
+}
+
+func TestStreamReader_RejectsOverflow(t *testing.T) {
+	t.Parallel()
+
+	maxDims := make([]int, MaxDimensions)
+	for i := range maxDims {
+		maxDims[i] = MaxCoordValue
+	}
+
+	data := writeStream(t, []Addr{NewHilbert(maxDims...)})
+
+	// A delta of 2^255 pushes past the top of the 256-bit range.
+	data = append(data, bytes.Repeat([]byte{varintMore}, maxVarintLen-1)...)
+	data = append(data, 1<<(255%varintBits))
+
+	sr := NewStreamReader(bytes.NewReader(data))
+	if _, err := sr.Read(); err != nil {
+		t.Fatalf("first Read() error = %v", err)
+	}
+
+	if _, err := sr.Read(); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Read(overflowing delta) error = %v, want %v", err, ErrMalformed)
+	}
+
+	// A final group carrying bits past 255 is rejected before summing.
+	wide := append([]byte{streamVersion}, bytes.Repeat([]byte{varintMore}, maxVarintLen-1)...)
+	wide = append(wide, 1<<(256%varintBits))
+
+	if _, err := NewStreamReader(bytes.NewReader(wide)).Read(); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Read(257-bit delta) error = %v, want %v", err, ErrMalformed)
+	}
+}
+
+func TestWide256Arithmetic(t *testing.T) {
+	t.Parallel()
+
+	a := Addr{^uint64(0), 0, 1, 0}
+	b := Addr{1, 0, 0, 0}
+
+	sum, carry := add256(a, b)
+	if sum != (Addr{0, 1, 1, 0}) || carry {
+		t.Errorf("add256() = %x, %v", sum, carry)
+	}
+
+	if diff := sub256(sum, b); diff != a {
+		t.Errorf("sub256() = %x, want %x", diff, a)
+	}
+
+	if _, carry := add256(Addr{0, 0, 0, 1 << 63}, Addr{0, 0, 0, 1 << 63}); !carry {
+		t.Error("add256() missed the carry out")
+	}
+
+	if got := shr256(Addr{0, 0, 0, 1}, 7); got != (Addr{0, 0, 1 << 57, 0}) {
+		t.Errorf("shr256() = %x", got)
+	}
+}
@@ -0,0 +1,186 @@
+package lattice
+
+import "fmt"
+
+// Reverse returns a new Addr with the coordinates in reverse order
+// e.g. Addr{1,2,3}.Reverse() → Addr{3,2,1}.
+func (a Addr) Reverse() Addr {
+	a.mustMorton()
+
+	coords, dims := a.Coords()
+
+	for i, j := 0, dims-1; i < j; i, j = i+1, j-1 {
+		coords[i], coords[j] = coords[j], coords[i]
+	}
+
+	return New(coords[:dims]...).withFlags(a)
+}
+
+// Swap returns a new Addr with the coordinates at dimensions i and j exchanged
+// e.g. Addr{1,2,3}.Swap(0, 2) → Addr{3,2,1}.
+// Panics if i or j is out of range [0, Dims()).
+func (a Addr) Swap(i, j int) Addr {
+	a.mustMorton()
+
+	coords, dims := a.Coords()
+
+	for _, dimIdx := range [2]int{i, j} {
+		if dimIdx < 0 || dimIdx >= dims {
+			panic(fmt.Sprintf("lattice: dimension index %d out of range [0:%d]", dimIdx, dims))
+		}
+	}
+
+	coords[i], coords[j] = coords[j], coords[i]
+
+	return New(coords[:dims]...).withFlags(a)
+}
+
+// Permute returns a new Addr with its dimensions rearranged so that output
+// dimension i holds input dimension perm[i]
+// e.g. Addr{x,y,z,t}.Permute([]int{3,0,1,2}) → Addr{t,x,y,z}.
+// Panics unless perm is a permutation of 0..Dims()-1: the right length,
+// every index in range and none repeated.
+func (a Addr) Permute(perm []int) Addr {
+	a.mustMorton()
+
+	coords, dims := a.Coords()
+	if len(perm) != dims {
+		panic(fmt.Sprintf("lattice: permutation %v has length %d, want %d", perm, len(perm), dims))
+	}
+
+	var (
+		out  Buffer
+		seen uint16
+	)
+
+	for i, src := range perm {
+		if src < 0 || src >= dims {
+			panic(fmt.Sprintf("lattice: permutation %v: index %d out of range [0:%d]", perm, src, dims))
+		}
+
+		if seen&(1<<src) != 0 {
+			panic(fmt.Sprintf("lattice: permutation %v: index %d repeated", perm, src))
+		}
+
+		seen |= 1 << src
+		out[i] = coords[src]
+	}
+
+	return New(out[:dims]...).withFlags(a)
+}
+
+// Map returns a new Addr whose coordinate in each dimension is f(dim, value)
+// e.g. Addr{8,4,12}.Map(func(_, v int) int { return v / 4 }) → Addr{2,1,3}.
+// f must return values in [0, MaxCoordValue]; otherwise Map panics as New does.
+func (a Addr) Map(f func(dim, value int) int) Addr {
+	a.mustMorton()
+
+	coords, dims := a.Coords()
+
+	for i := range dims {
+		coords[i] = f(i, coords[i])
+	}
+
+	return New(coords[:dims]...).withFlags(a)
+}
+
+// Scale returns a new Addr with every coordinate multiplied by factor
+// e.g. Addr{1,2,3}.Scale(4) → Addr{4,8,12}.
+// Panics if factor is negative, any result exceeds MaxCoordValue, or a is
+// signed or Hilbert-encoded.
+func (a Addr) Scale(factor int) Addr {
+	addr, err := a.TryScale(factor)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return addr
+}
+
+// TryScale is like Scale but returns an error wrapping ErrCoordRange,
+// naming the first coordinate that would overflow, ErrHilbert or ErrSigned
+// instead of panicking. On error the receiver is returned unchanged.
+func (a Addr) TryScale(factor int) (Addr, error) {
+	if factor < 0 {
+		return a, fmt.Errorf("%w: scale factor %d is negative", ErrCoordRange, factor)
+	}
+
+	if err := a.checkPlain(); err != nil {
+		return a, err
+	}
+
+	coords, dims := a.Coords()
+
+	for i := range dims {
+		if factor > 0 && coords[i] > MaxCoordValue/factor {
+			return a, fmt.Errorf("%w: coord[%d]=%d scaled by %d exceeds %d", ErrCoordRange, i, coords[i], factor, MaxCoordValue)
+		}
+
+		coords[i] *= factor
+	}
+
+	return New(coords[:dims]...).withFlags(a), nil
+}
+
+// Quantize returns a new Addr with every coordinate snapped down to its
+// bucket index e.g. Addr{0,7,8,15}.Quantize(8) → Addr{0,0,1,1}.
+// Panics if bucket <= 0 or a is signed or Hilbert-encoded.
+func (a Addr) Quantize(bucket int) Addr {
+	if bucket <= 0 {
+		panic(fmt.Sprintf("lattice: quantize bucket %d must be positive", bucket))
+	}
+
+	a.mustPlain()
+
+	return a.Map(func(_, value int) int { return value / bucket })
+}
+
+// Pad returns the address extended to dims dimensions by appending fill
+// e.g. Addr{1,2}.Pad(4, 0) → Addr{1,2,0,0}.
+// An address that already has dims or more dimensions is returned
+// unchanged; use Truncate to shorten one. No allocation takes place.
+// Panics if dims exceeds MaxDimensions or fill is out of range.
+func (a Addr) Pad(dims, fill int) Addr {
+	if dims > MaxDimensions {
+		panic(fmt.Sprintf("lattice: max %d dimensions supported", MaxDimensions))
+	}
+
+	if fill < 0 || fill > MaxCoordValue {
+		panic(fmt.Sprintf("lattice: pad fill %d out of range [0,%d]", fill, MaxCoordValue))
+	}
+
+	a.mustMorton()
+
+	coords, n := a.Coords()
+	if dims <= n {
+		return a
+	}
+
+	for i := n; i < dims; i++ {
+		coords[i] = fill
+	}
+
+	return New(coords[:dims]...).withFlags(a)
+}
+
+// Truncate returns the address with every dimension from dims onwards
+// dropped e.g. Addr{1,2,3}.Truncate(2) → Addr{1,2}.
+// It is Slice(0, dims) except that an address with dims or fewer
+// dimensions is returned unchanged instead of panicking.
+// Panics if dims is negative.
+func (a Addr) Truncate(dims int) Addr {
+	return a.Slice(0, min(dims, a.Dims()))
+}
+
+// SetDims returns the address reshaped to exactly n dimensions, keeping
+// its leading coordinates: truncated like Truncate if n < Dims(), padded
+// with fill like Pad if n > Dims(). SetDims(a.Dims(), fill) is the
+// identity. Panics if n is outside [0, MaxDimensions] or fill is out of
+// range, even when no padding is needed.
+func (a Addr) SetDims(n, fill int) Addr {
+	if n < 0 || n > MaxDimensions {
+		panic(fmt.Sprintf("lattice: dimension count %d out of range [0,%d]", n, MaxDimensions))
+	}
+
+	return a.Pad(n, fill).Truncate(n)
+}
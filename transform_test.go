@@ -0,0 +1,486 @@
+package lattice_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/aclivo/lattice"
+)
+
+// ============================================================
+// Reverse / Swap
+// ============================================================
+
+func TestReverse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		addr lattice.Addr
+		want lattice.Addr
+	}{
+		{lattice.New(), lattice.New()},
+		{lattice.New(1), lattice.New(1)},
+		{lattice.New(1, 2), lattice.New(2, 1)},
+		{lattice.New(1, 2, 3), lattice.New(3, 2, 1)},
+		{lattice.New(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12), lattice.New(12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.addr.String(), func(t *testing.T) {
+			t.Parallel()
+
+			got := testCase.addr.Reverse()
+			if got != testCase.want {
+				t.Errorf("Reverse() = %v, want %v", got, testCase.want)
+			}
+
+			if twice := got.Reverse(); twice != testCase.addr {
+				t.Errorf("Reverse().Reverse() = %v, want %v", twice, testCase.addr)
+			}
+		})
+	}
+}
+
+func TestSwap(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		i, j int
+		want lattice.Addr
+	}{
+		{0, 0, lattice.New(1, 2, 3, 4)},
+		{0, 3, lattice.New(4, 2, 3, 1)},
+		{3, 0, lattice.New(4, 2, 3, 1)},
+		{1, 2, lattice.New(1, 3, 2, 4)},
+	}
+
+	addr := lattice.New(1, 2, 3, 4)
+
+	for _, testCase := range tests {
+		t.Run(fmt.Sprintf("%d,%d", testCase.i, testCase.j), func(t *testing.T) {
+			t.Parallel()
+
+			got := addr.Swap(testCase.i, testCase.j)
+			if got != testCase.want {
+				t.Errorf("Swap(%d, %d) = %v, want %v", testCase.i, testCase.j, got, testCase.want)
+			}
+
+			if twice := got.Swap(testCase.i, testCase.j); twice != addr {
+				t.Errorf("Swap().Swap() = %v, want %v", twice, addr)
+			}
+		})
+	}
+}
+
+func TestSwap_PanicMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		i, j    int
+		wantMsg string
+	}{
+		{-1, 0, "lattice: dimension index -1 out of range [0:3]"},
+		{0, 3, "lattice: dimension index 3 out of range [0:3]"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(fmt.Sprintf("%d,%d", testCase.i, testCase.j), func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				rec := recover()
+
+				if rec == nil {
+					t.Error("expected panic")
+
+					return
+				}
+
+				if got := fmt.Sprintf("%v", rec); got != testCase.wantMsg {
+					t.Errorf("panic message = %q, want %q", got, testCase.wantMsg)
+				}
+			}()
+
+			lattice.New(1, 2, 3).Swap(testCase.i, testCase.j)
+		})
+	}
+}
+
+// ============================================================
+// Permute
+// ============================================================
+
+func TestPermute(t *testing.T) {
+	t.Parallel()
+
+	addr := lattice.New(10, 20, 30, 40)
+
+	tests := []struct {
+		name string
+		perm []int
+		want lattice.Addr
+	}{
+		{"identity", []int{0, 1, 2, 3}, addr},
+		{"reversal", []int{3, 2, 1, 0}, addr.Reverse()},
+		{"rotate t to front", []int{3, 0, 1, 2}, lattice.New(40, 10, 20, 30)},
+		{"swap", []int{0, 2, 1, 3}, addr.Swap(1, 2)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := addr.Permute(testCase.perm); got != testCase.want {
+				t.Errorf("Permute(%v) = %v, want %v", testCase.perm, got, testCase.want)
+			}
+		})
+	}
+
+	if got := lattice.New().Permute(nil); got != lattice.New() {
+		t.Errorf("empty Permute(nil) = %v, want %v", got, lattice.New())
+	}
+}
+
+func TestPermute_PanicInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		perm    []int
+		wantMsg string
+	}{
+		{"too short", []int{0, 1}, "lattice: permutation [0 1] has length 2, want 3"},
+		{"too long", []int{0, 1, 2, 3}, "lattice: permutation [0 1 2 3] has length 4, want 3"},
+		{"out of range", []int{0, 1, 3}, "lattice: permutation [0 1 3]: index 3 out of range [0:3]"},
+		{"negative", []int{0, -1, 2}, "lattice: permutation [0 -1 2]: index -1 out of range [0:3]"},
+		{"duplicate", []int{0, 1, 1}, "lattice: permutation [0 1 1]: index 1 repeated"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				rec := recover()
+
+				if rec == nil {
+					t.Error("expected panic")
+
+					return
+				}
+
+				if got := fmt.Sprintf("%v", rec); got != testCase.wantMsg {
+					t.Errorf("panic message = %q, want %q", got, testCase.wantMsg)
+				}
+			}()
+
+			lattice.New(1, 2, 3).Permute(testCase.perm)
+		})
+	}
+}
+
+// ============================================================
+// Map
+// ============================================================
+
+func TestMap(t *testing.T) {
+	t.Parallel()
+
+	half := func(_, v int) int { return v / 2 }
+
+	if got, want := lattice.New(10, 21, 0, lattice.MaxCoordValue).Map(half), lattice.New(5, 10, 0, lattice.MaxCoordValue/2); got != want {
+		t.Errorf("Map(half) = %v, want %v", got, want)
+	}
+
+	byDim := func(dim, v int) int { return v + dim }
+
+	if got, want := lattice.New(1, 1, 1).Map(byDim), lattice.New(1, 2, 3); got != want {
+		t.Errorf("Map(byDim) = %v, want %v", got, want)
+	}
+
+	if got := lattice.New().Map(half); got != lattice.New() {
+		t.Errorf("empty Map = %v, want %v", got, lattice.New())
+	}
+}
+
+func TestMap_PanicOverflow(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		rec := recover()
+
+		want := fmt.Sprintf("lattice: coord[1]=%d out of range [0,%d]", 2*lattice.MaxCoordValue, lattice.MaxCoordValue)
+		if got := fmt.Sprintf("%v", rec); got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	lattice.New(0, lattice.MaxCoordValue).Map(func(_, v int) int { return v * 2 })
+}
+
+// ============================================================
+// Scale / Quantize
+// ============================================================
+
+func TestScale(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		addr   lattice.Addr
+		factor int
+		want   lattice.Addr
+	}{
+		{"by four", lattice.New(1, 2, 3), 4, lattice.New(4, 8, 12)},
+		{"by one", lattice.New(1, 2, 3), 1, lattice.New(1, 2, 3)},
+		{"by zero", lattice.New(1, 2, 3), 0, lattice.New(0, 0, 0)},
+		{"to max", lattice.New(lattice.MaxCoordValue), 1, lattice.New(lattice.MaxCoordValue)},
+		{"empty", lattice.New(), 7, lattice.New()},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := testCase.addr.Scale(testCase.factor); got != testCase.want {
+				t.Errorf("%v.Scale(%d) = %v, want %v", testCase.addr, testCase.factor, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestScale_Panics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		addr    lattice.Addr
+		factor  int
+		wantMsg string
+	}{
+		{"overflow", lattice.New(1, 1<<19), 2, "lattice: coordinate out of range: coord[1]=524288 scaled by 2 exceeds 1048575"},
+		{
+			"huge factor",
+			lattice.New(0, 2),
+			math.MaxInt,
+			fmt.Sprintf("lattice: coordinate out of range: coord[1]=2 scaled by %d exceeds 1048575", math.MaxInt),
+		},
+		{"negative", lattice.New(1), -1, "lattice: coordinate out of range: scale factor -1 is negative"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != testCase.wantMsg {
+					t.Errorf("panic message = %q, want %q", got, testCase.wantMsg)
+				}
+			}()
+
+			testCase.addr.Scale(testCase.factor)
+		})
+	}
+}
+
+func TestQuantize(t *testing.T) {
+	t.Parallel()
+
+	if got, want := lattice.New(0, 7, 8, 15, 16).Quantize(8), lattice.New(0, 0, 1, 1, 2); got != want {
+		t.Errorf("Quantize(8) = %v, want %v", got, want)
+	}
+
+	// Every coordinate lands in the bucket whose scaled origin lies at or below it.
+	for _, addr := range []lattice.Addr{lattice.New(0, 1, 2), lattice.New(99, 100, 101), lattice.New(lattice.MaxCoordValue, 12345)} {
+		for _, bucket := range []int{1, 3, 10, 1024} {
+			lo := addr.Quantize(bucket).Scale(bucket)
+
+			for dim := range addr.Dims() {
+				v, origin := addr.At(dim), lo.At(dim)
+				if origin > v || v-origin >= bucket {
+					t.Errorf("%v.Quantize(%d) dim %d: origin %d does not bucket %d", addr, bucket, dim, origin, v)
+				}
+			}
+		}
+	}
+}
+
+func TestQuantize_PanicNonPositive(t *testing.T) {
+	t.Parallel()
+
+	for _, bucket := range []int{0, -4} {
+		func() {
+			defer func() {
+				want := fmt.Sprintf("lattice: quantize bucket %d must be positive", bucket)
+				if got := fmt.Sprintf("%v", recover()); got != want {
+					t.Errorf("panic message = %q, want %q", got, want)
+				}
+			}()
+
+			lattice.New(1, 2).Quantize(bucket)
+		}()
+	}
+}
+
+// ============================================================
+// Pad / Truncate
+// ============================================================
+
+func TestPad(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		a          lattice.Addr
+		dims, fill int
+		want       lattice.Addr
+	}{
+		{"2 to 5", lattice.New(1, 2), 5, 0, lattice.New(1, 2, 0, 0, 0)},
+		{"custom fill", lattice.New(7), 3, 9, lattice.New(7, 9, 9)},
+		{"from empty", lattice.New(), 2, lattice.MaxCoordValue, lattice.New(lattice.MaxCoordValue, lattice.MaxCoordValue)},
+		{"to max", lattice.New(1), lattice.MaxDimensions, 4, lattice.New(1, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4)},
+		{"already wide enough", lattice.New(1, 2, 3), 3, 5, lattice.New(1, 2, 3)},
+		{"wider than dims", lattice.New(1, 2, 3), 2, 5, lattice.New(1, 2, 3)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := testCase.a.Pad(testCase.dims, testCase.fill); got != testCase.want {
+				t.Errorf("%v.Pad(%d, %d) = %v, want %v", testCase.a, testCase.dims, testCase.fill, got, testCase.want)
+			}
+		})
+	}
+}
+
+func TestPad_Panics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		dims, fill int
+		want       string
+	}{
+		{"too many dims", lattice.MaxDimensions + 1, 0, "lattice: max 12 dimensions supported"},
+		{"negative fill", 4, -1, "lattice: pad fill -1 out of range [0,1048575]"},
+		{"fill too large", 4, lattice.MaxCoordValue + 1, "lattice: pad fill 1048576 out of range [0,1048575]"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != testCase.want {
+					t.Errorf("panic message = %q, want %q", got, testCase.want)
+				}
+			}()
+
+			lattice.New(1, 2).Pad(testCase.dims, testCase.fill)
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	t.Parallel()
+
+	a := lattice.New(1, 2, 3, 4, 5)
+
+	tests := []struct {
+		dims int
+		want lattice.Addr
+	}{
+		{2, lattice.New(1, 2)},
+		{0, lattice.New()},
+		{5, a},
+		{9, a},
+	}
+
+	for _, testCase := range tests {
+		if got := a.Truncate(testCase.dims); got != testCase.want {
+			t.Errorf("Truncate(%d) = %v, want %v", testCase.dims, got, testCase.want)
+		}
+	}
+
+	if got := a.Truncate(2).Pad(5, 0); got != lattice.New(1, 2, 0, 0, 0) {
+		t.Errorf("Truncate(2).Pad(5, 0) = %v", got)
+	}
+}
+
+func TestTruncate_PanicNegative(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if got, want := fmt.Sprintf("%v", recover()), "lattice: slice [0:-1] out of range [0:3]"; got != want {
+			t.Errorf("panic message = %q, want %q", got, want)
+		}
+	}()
+
+	lattice.New(1, 2, 3).Truncate(-1)
+}
+
+// ============================================================
+// SetDims
+// ============================================================
+
+func TestSetDims(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		a       lattice.Addr
+		n, fill int
+		want    lattice.Addr
+	}{
+		{"grow", lattice.New(1, 2), 4, 7, lattice.New(1, 2, 7, 7)},
+		{"shrink", lattice.New(1, 2, 3, 4, 5), 2, 7, lattice.New(1, 2)},
+		{"identity", lattice.New(1, 2, 3), 3, 7, lattice.New(1, 2, 3)},
+		{"to empty", lattice.New(1, 2, 3), 0, 0, lattice.New()},
+		{"from empty", lattice.New(), 1, 5, lattice.New(5)},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := testCase.a.SetDims(testCase.n, testCase.fill)
+			if got != testCase.want {
+				t.Errorf("%v.SetDims(%d, %d) = %v, want %v", testCase.a, testCase.n, testCase.fill, got, testCase.want)
+			}
+
+			if got.Dims() != testCase.n {
+				t.Errorf("Dims() = %d, want %d", got.Dims(), testCase.n)
+			}
+		})
+	}
+}
+
+func TestSetDims_Panics(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		n, fill int
+		want    string
+	}{
+		{"negative", -1, 0, "lattice: dimension count -1 out of range [0,12]"},
+		{"too many", lattice.MaxDimensions + 1, 0, "lattice: dimension count 13 out of range [0,12]"},
+		{"bad fill when shrinking", 1, -1, "lattice: pad fill -1 out of range [0,1048575]"},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprintf("%v", recover()); got != testCase.want {
+					t.Errorf("panic message = %q, want %q", got, testCase.want)
+				}
+			}()
+
+			lattice.New(1, 2, 3).SetDims(testCase.n, testCase.fill)
+		})
+	}
+}